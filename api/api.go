@@ -0,0 +1,61 @@
+// Package api holds the OpenAPI document describing the HTTP API and serves
+// it together with a documentation page.
+package api
+
+import (
+	"context"
+	"embed"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	//go:embed openapi.json
+	spec []byte
+	// docs renders the document without loading anything from other hosts
+	//go:embed docs
+	docs embed.FS
+)
+
+// docsPolicy keeps the docs page to the assets served with it.
+const docsPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self' data:"
+
+// Spec returns the OpenAPI document as JSON.
+func Spec() []byte {
+	return spec
+}
+
+// Load parses and validates the OpenAPI document.
+func Load(ctx context.Context) (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// NewDocsHandler serves the document at /openapi.json and a page rendering
+// it at /docs, with its script and stylesheet embedded in the binary.
+func NewDocsHandler(router *gin.Engine) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+	asset := func(name, contentType string) gin.HandlerFunc {
+		data, err := docs.ReadFile("docs/" + name)
+		if err != nil {
+			panic(err)
+		}
+		return func(c *gin.Context) {
+			c.Header("Content-Security-Policy", docsPolicy)
+			c.Data(http.StatusOK, contentType, data)
+		}
+	}
+	router.GET("/docs", asset("index.html", "text/html; charset=utf-8"))
+	router.GET("/docs/docs.js", asset("docs.js", "text/javascript; charset=utf-8"))
+	router.GET("/docs/docs.css", asset("docs.css", "text/css; charset=utf-8"))
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	v1 "geniuscrew/api/v1"
+	_authorHandler "geniuscrew/author/handler/http"
+	_bookHandler "geniuscrew/book/handler/http"
+	"geniuscrew/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// TestSpecMatchesRoutes fails when a route is registered without being
+// documented, or documented without being registered.
+func TestSpecMatchesRoutes(t *testing.T) {
+	as := assert.New(t)
+	doc, err := Load(context.Background())
+	as.NoError(err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	_bookHandler.NewBookHandler(router, nil, v1.Mapper{})
+	_authorHandler.NewAuthorHandler(router, nil, v1.Mapper{})
+	var registered []string
+	for _, route := range router.Routes() {
+		registered = append(registered, route.Method+" "+pathParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(registered)
+	sort.Strings(documented)
+	as.Equal(registered, documented)
+}
+
+func TestValidateRequests(t *testing.T) {
+	as := assert.New(t)
+	doc, err := Load(context.Background())
+	as.NoError(err)
+	validate, err := middleware.ValidateRequests(doc)
+	as.NoError(err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(validate)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.POST("/api/v1/books", ok)
+	router.GET("/api/v1/books/filter", ok)
+	router.GET("/undocumented", ok)
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"valid body", http.MethodPost, "/api/v1/books", `{"title":"Go","description":"d","ISBN":"978160309028","publishing_company":"p"}`, http.StatusNoContent},
+		{"missing required field", http.MethodPost, "/api/v1/books", `{"title":"Go"}`, http.StatusUnprocessableEntity},
+		{"field too long", http.MethodPost, "/api/v1/books", `{"title":"Go","description":"d","ISBN":"978160309028978160309028","publishing_company":"p"}`, http.StatusUnprocessableEntity},
+		{"query parameter outside the enum", http.MethodGet, "/api/v1/books/filter?field=isbn&value=1", "", http.StatusUnprocessableEntity},
+		{"malformed body", http.MethodPost, "/api/v1/books", `{"title":`, http.StatusBadRequest},
+		{"XML body is left to the handler", http.MethodPost, "/api/v1/books", `<book><title>Go</title></book>`, http.StatusNoContent},
+		{"route missing from the spec", http.MethodGet, "/undocumented", "", http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if strings.HasPrefix(tc.body, "<") {
+				req.Header.Set("Content-Type", "application/xml")
+			} else if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			as.Equal(tc.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// TestDocs checks that the docs page is served with its assets and loads
+// nothing from other hosts.
+func TestDocs(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewDocsHandler(router)
+
+	for _, path := range []string{"/docs", "/docs/docs.js", "/docs/docs.css"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		as.Equal(http.StatusOK, w.Code, path)
+		as.Contains(w.Header().Get("Content-Security-Policy"), "script-src 'self'", path)
+		as.NotRegexp(`(src|href)="(https?:)?//`, w.Body.String(), path)
+	}
+}
@@ -0,0 +1,152 @@
+// Renders the OpenAPI document served at /openapi.json. It is served by the
+// API itself, so the page works offline and under a same-origin CSP.
+(function () {
+  "use strict";
+
+  var main = document.getElementById("main");
+  var nav = document.getElementById("nav");
+  var methods = ["get", "put", "post", "patch", "delete", "head", "options"];
+  var doc;
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) { node.setAttribute(k, attrs[k]); });
+    (children || []).forEach(function (c) {
+      if (c === null || c === undefined) return;
+      node.appendChild(typeof c === "string" ? document.createTextNode(c) : c);
+    });
+    return node;
+  }
+
+  function resolve(obj) {
+    var seen = 0;
+    while (obj && obj.$ref && seen++ < 16) {
+      obj = obj.$ref.replace(/^#\//, "").split("/").reduce(function (o, k) {
+        return o && o[k.replace(/~1/g, "/").replace(/~0/g, "~")];
+      }, doc);
+    }
+    return obj || {};
+  }
+
+  function refName(ref) {
+    return ref.split("/").pop();
+  }
+
+  // schemaText describes a schema in a compact, TypeScript-like notation,
+  // naming referenced schemas instead of expanding them.
+  function schemaText(schema, indent) {
+    indent = indent || "";
+    if (!schema) return "any";
+    if (schema.$ref) return refName(schema.$ref);
+    var variants = schema.oneOf || schema.anyOf;
+    if (variants) return variants.map(function (s) { return schemaText(s, indent); }).join(" | ");
+    if (schema.allOf) return schema.allOf.map(function (s) { return schemaText(s, indent); }).join(" & ");
+    var type = Array.isArray(schema.type) ? schema.type.join(" | ") : schema.type;
+    if (type === "array" || schema.items) return "[" + schemaText(schema.items, indent) + "]";
+    if (type === "object" || schema.properties) {
+      var props = schema.properties || {};
+      var required = schema.required || [];
+      var names = Object.keys(props);
+      if (!names.length) return "object";
+      var inner = indent + "  ";
+      return "{\n" + names.map(function (n) {
+        return inner + n + (required.indexOf(n) >= 0 ? "" : "?") + ": " + schemaText(props[n], inner);
+      }).join(",\n") + "\n" + indent + "}";
+    }
+    var text = type || "any";
+    if (schema.enum) text = schema.enum.map(function (v) { return JSON.stringify(v); }).join(" | ");
+    if (schema.format) text += " (" + schema.format + ")";
+    return text;
+  }
+
+  function content(media) {
+    var types = Object.keys(media || {});
+    if (!types.length) return null;
+    return el("div", {}, [
+      el("div", { class: "muted" }, [types.join(", ")]),
+      el("pre", {}, [schemaText(media[types[0]].schema)])
+    ]);
+  }
+
+  function parameters(params) {
+    if (!params.length) return null;
+    return el("table", {}, [el("tr", {}, [el("th", {}, ["Name"]), el("th", {}, ["In"]), el("th", {}, ["Type"]), el("th", {}, ["Description"])])].concat(
+      params.map(function (p) {
+        p = resolve(p);
+        return el("tr", {}, [
+          el("td", {}, [el("code", {}, [p.name + (p.required ? "" : "?")])]),
+          el("td", {}, [p.in]),
+          el("td", {}, [el("code", {}, [schemaText(p.schema)])]),
+          el("td", {}, [p.description || ""])
+        ]);
+      })));
+  }
+
+  function operation(path, method, op, shared) {
+    var id = op.operationId || method + path;
+    var body = op.requestBody && resolve(op.requestBody);
+    var responses = Object.keys(op.responses || {}).map(function (status) {
+      var r = resolve(op.responses[status]);
+      return el("div", {}, [el("strong", {}, [status]), " " + (r.description || ""), content(r.content)]);
+    });
+    return el("section", { class: "operation", id: id }, [
+      el("h3", {}, [el("span", { class: "method " + method }, [method]), el("code", {}, [path])]),
+      op.summary ? el("p", {}, [el("strong", {}, [op.summary])]) : null,
+      op.description ? el("p", {}, [op.description]) : null,
+      parameters(shared.concat(op.parameters || [])),
+      body ? el("h4", {}, ["Request body"]) : null,
+      body ? content(body.content) : null,
+      el("h4", {}, ["Responses"])
+    ].concat(responses));
+  }
+
+  function render() {
+    var info = doc.info || {};
+    main.textContent = "";
+    nav.textContent = "";
+    main.appendChild(el("h1", {}, [(info.title || "API") + " " + (info.version || "")]));
+    if (info.description) main.appendChild(el("p", {}, [info.description]));
+    main.appendChild(el("p", {}, [el("a", { href: "/openapi.json" }, ["Download the OpenAPI document"])]));
+
+    var byTag = {};
+    Object.keys(doc.paths || {}).forEach(function (path) {
+      var item = doc.paths[path];
+      methods.forEach(function (method) {
+        var op = item[method];
+        if (!op) return;
+        var tag = (op.tags || ["other"])[0];
+        (byTag[tag] = byTag[tag] || []).push(operation(path, method, op, item.parameters || []));
+      });
+    });
+    Object.keys(byTag).forEach(function (tag) {
+      main.appendChild(el("h2", { id: "tag-" + tag }, [tag]));
+      nav.appendChild(el("h3", {}, [tag]));
+      byTag[tag].forEach(function (section) {
+        main.appendChild(section);
+        nav.appendChild(el("a", { href: "#" + section.id }, [section.querySelector("h3").textContent]));
+      });
+    });
+
+    var schemas = (doc.components || {}).schemas || {};
+    if (Object.keys(schemas).length) {
+      main.appendChild(el("h2", { id: "schemas" }, ["Schemas"]));
+      nav.appendChild(el("h3", {}, ["schemas"]));
+      Object.keys(schemas).forEach(function (name) {
+        main.appendChild(el("section", { class: "operation", id: "schema-" + name }, [
+          el("h3", {}, [el("code", {}, [name])]),
+          schemas[name].description ? el("p", {}, [schemas[name].description]) : null,
+          el("pre", {}, [schemaText(Object.assign({}, schemas[name], { $ref: undefined }))])
+        ]));
+        nav.appendChild(el("a", { href: "#schema-" + name }, [name]));
+      });
+    }
+  }
+
+  fetch("/openapi.json")
+    .then(function (res) {
+      if (!res.ok) throw new Error("GET /openapi.json: " + res.status);
+      return res.json();
+    })
+    .then(function (d) { doc = d; render(); })
+    .catch(function (err) { main.textContent = "Could not load the API document: " + err.message; });
+})();
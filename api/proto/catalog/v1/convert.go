@@ -0,0 +1,84 @@
+package catalogv1
+
+import (
+	apiv1 "geniuscrew/api/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromBook converts the v1 representation of a book to its message, so the
+// gRPC API nests associations exactly as deep as the REST one.
+func FromBook(b apiv1.Book) *Book {
+	return &Book{
+		Id:                int64(b.ID),
+		Title:             b.Title,
+		Description:       b.Description,
+		Isbn:              b.ISBN,
+		PublicationDate:   b.PublicationDate,
+		PublishingCompany: b.PublishingCompany,
+		Authors:           fromAuthorSummaries(b.Authors),
+		CreatedAt:         timestamppb.New(b.CreatedAt),
+		UpdatedAt:         timestamppb.New(b.UpdatedAt),
+	}
+}
+
+func FromBooks(books []apiv1.Book) []*Book {
+	out := make([]*Book, 0, len(books))
+	for _, b := range books {
+		out = append(out, FromBook(b))
+	}
+	return out
+}
+
+// FromAuthor converts the v1 representation of an author to its message.
+func FromAuthor(a apiv1.Author) *Author {
+	return &Author{
+		Id:             int64(a.ID),
+		Name:           a.Name,
+		Surname:        a.Surname,
+		Email:          a.Email,
+		BooksPublished: fromBookSummaries(a.BooksPublished),
+		CreatedAt:      timestamppb.New(a.CreatedAt),
+		UpdatedAt:      timestamppb.New(a.UpdatedAt),
+	}
+}
+
+func FromAuthors(authors []apiv1.Author) []*Author {
+	out := make([]*Author, 0, len(authors))
+	for _, a := range authors {
+		out = append(out, FromAuthor(a))
+	}
+	return out
+}
+
+func fromBookSummaries(books []apiv1.BookSummary) []*BookSummary {
+	if len(books) == 0 {
+		return nil
+	}
+	out := make([]*BookSummary, 0, len(books))
+	for _, b := range books {
+		out = append(out, &BookSummary{
+			Id:      int64(b.ID),
+			Title:   b.Title,
+			Isbn:    b.ISBN,
+			Authors: fromAuthorSummaries(b.Authors),
+		})
+	}
+	return out
+}
+
+func fromAuthorSummaries(authors []apiv1.AuthorSummary) []*AuthorSummary {
+	if len(authors) == 0 {
+		return nil
+	}
+	out := make([]*AuthorSummary, 0, len(authors))
+	for _, a := range authors {
+		out = append(out, &AuthorSummary{
+			Id:             int64(a.ID),
+			Name:           a.Name,
+			Surname:        a.Surname,
+			BooksPublished: fromBookSummaries(a.BooksPublished),
+		})
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"geniuscrew/domain"
+	"time"
+)
+
+// Author is the v1 representation of an author. Their books are summaries.
+type Author struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	Surname        string        `json:"surname"`
+	Email          string        `json:"email"`
+	BooksPublished []BookSummary `json:"books_published"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// AuthorSummary is an author nested in another resource.
+type AuthorSummary struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	Surname        string        `json:"surname"`
+	BooksPublished []BookSummary `json:"books_published,omitempty"`
+}
+
+type CreateAuthorRequest struct {
+	Name           string   `json:"name" xml:"name" validate:"gte=0,lte=500,required"`
+	Surname        string   `json:"surname" xml:"surname" validate:"gte=0,lte=500,required"`
+	Email          string   `json:"email" xml:"email" validate:"email,required"`
+	BooksPublished []string `json:"books_published" xml:"books_published>item" validate:"min=1,dive,required"`
+}
+
+func (r CreateAuthorRequest) Author() domain.Author {
+	return domain.Author{Name: r.Name, Surname: r.Surname, Email: r.Email}
+}
+
+type UpdateAuthorRequest struct {
+	Name           string   `json:"name" xml:"name" validate:"isdefault|gte=0,lte=500"`
+	Surname        string   `json:"surname" xml:"surname" validate:"isdefault|gte=0,lte=500"`
+	Email          string   `json:"email" xml:"email" validate:"isdefault|email"`
+	BooksPublished []string `json:"books_published" xml:"books_published>item" validate:"isdefault|min=1,dive,required"`
+}
+
+// Author returns the fields to change; zero values are left untouched.
+func (r UpdateAuthorRequest) Author() domain.Author {
+	return domain.Author{Name: r.Name, Surname: r.Surname, Email: r.Email}
+}
+
+func (m Mapper) Author(a domain.Author) Author {
+	return Author{
+		ID:             a.ID,
+		Name:           a.Name,
+		Surname:        a.Surname,
+		Email:          a.Email,
+		BooksPublished: m.bookSummaries(a.BooksPublished, m.depth()-1),
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
+
+func (m Mapper) Authors(authors []domain.Author) []Author {
+	out := make([]Author, 0, len(authors))
+	for _, a := range authors {
+		out = append(out, m.Author(a))
+	}
+	return out
+}
+
+// authorSummaries summarizes authors, nesting their books depth more levels.
+// Emails are left out of summaries.
+func (m Mapper) authorSummaries(authors []domain.Author, depth int) []AuthorSummary {
+	out := make([]AuthorSummary, 0, len(authors))
+	for _, a := range authors {
+		summary := AuthorSummary{ID: a.ID, Name: a.Name, Surname: a.Surname}
+		if depth > 0 {
+			summary.BooksPublished = m.bookSummaries(a.BooksPublished, depth-1)
+		}
+		out = append(out, summary)
+	}
+	return out
+}
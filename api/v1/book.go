@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"geniuscrew/domain"
+	"time"
+)
+
+// Book is the v1 representation of a book. Its authors are summaries, so the
+// payload no longer recurses through the persistence model.
+type Book struct {
+	ID                int             `json:"id"`
+	Title             string          `json:"title"`
+	Description       string          `json:"description"`
+	ISBN              string          `json:"ISBN"`
+	PublicationDate   string          `json:"publication_date"`
+	PublishingCompany string          `json:"publishing_company"`
+	Authors           []AuthorSummary `json:"authors"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// BookSummary is a book nested in another resource.
+type BookSummary struct {
+	ID      int             `json:"id"`
+	Title   string          `json:"title"`
+	ISBN    string          `json:"ISBN"`
+	Authors []AuthorSummary `json:"authors,omitempty"`
+}
+
+type CreateBookRequest struct {
+	Title             string `json:"title" xml:"title" validate:"gte=0,lte=500,required"`
+	Description       string `json:"description" xml:"description" validate:"gte=0,lte=500,required"`
+	ISBN              string `json:"ISBN" xml:"ISBN" validate:"gte=0,lte=14,required"`
+	PublishingCompany string `json:"publishing_company" xml:"publishing_company" validate:"gte=0,lte=50,required"`
+}
+
+func (r CreateBookRequest) Book() domain.Book {
+	return domain.Book{
+		Title:             r.Title,
+		Description:       r.Description,
+		ISBN:              r.ISBN,
+		PublishingCompany: r.PublishingCompany,
+	}
+}
+
+type UpdateBookRequest struct {
+	Title             string `json:"title" xml:"title" validate:"isdefault|gte=0,lte=500"`
+	Description       string `json:"description" xml:"description" validate:"isdefault|gte=0,lte=500"`
+	ISBN              string `json:"ISBN" xml:"ISBN" validate:"isdefault|gte=0,lte=14"`
+	PublishingCompany string `json:"publishing_company" xml:"publishing_company" validate:"isdefault|gte=0, lte=50"`
+}
+
+// Book returns the fields to change; zero values are left untouched.
+func (r UpdateBookRequest) Book() domain.Book {
+	return domain.Book{
+		Title:             r.Title,
+		Description:       r.Description,
+		ISBN:              r.ISBN,
+		PublishingCompany: r.PublishingCompany,
+	}
+}
+
+func (m Mapper) Book(b domain.Book) Book {
+	return Book{
+		ID:                b.ID,
+		Title:             b.Title,
+		Description:       b.Description,
+		ISBN:              b.ISBN,
+		PublicationDate:   b.PublicationDate,
+		PublishingCompany: b.PublishingCompany,
+		Authors:           m.authorSummaries(b.Authors, m.depth()-1),
+		CreatedAt:         b.CreatedAt,
+		UpdatedAt:         b.UpdatedAt,
+	}
+}
+
+func (m Mapper) Books(books []domain.Book) []Book {
+	out := make([]Book, 0, len(books))
+	for _, b := range books {
+		out = append(out, m.Book(b))
+	}
+	return out
+}
+
+// bookSummaries summarizes books, nesting their authors depth more levels.
+func (m Mapper) bookSummaries(books []domain.Book, depth int) []BookSummary {
+	out := make([]BookSummary, 0, len(books))
+	for _, b := range books {
+		summary := BookSummary{ID: b.ID, Title: b.Title, ISBN: b.ISBN}
+		if depth > 0 {
+			summary.Authors = m.authorSummaries(b.Authors, depth-1)
+		}
+		out = append(out, summary)
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"encoding/json"
+	"geniuscrew/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deleted is the data of book.deleted and author.deleted events.
+type Deleted struct {
+	ID int `json:"id"`
+}
+
+// AuthorBooksChanged is the data of author.books_changed events: the ISBNs
+// of the books now linked to the author and of those linked and unlinked.
+type AuthorBooksChanged struct {
+	ID             int      `json:"id"`
+	BooksPublished []string `json:"books_published"`
+	Added          []string `json:"added"`
+	Removed        []string `json:"removed"`
+}
+
+// Change operations.
+const (
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationLink   = "link"
+)
+
+// Change is an entry of the change feed. Data is the new state of the book or
+// author, null for a delete, and the linked books for a link change.
+type Change struct {
+	Seq        int             `json:"seq"`
+	EventID    string          `json:"event_id"`
+	Type       string          `json:"type"`
+	Operation  string          `json:"operation"`
+	Entity     string          `json:"entity"`
+	ID         int             `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewChange returns the change event records.
+func NewChange(event domain.Event) (Change, error) {
+	entity, id, _ := strings.Cut(event.Aggregate, "/")
+	change := Change{
+		Seq:        event.Seq,
+		EventID:    event.ID,
+		Type:       event.Type,
+		Operation:  operation(event.Type),
+		Entity:     entity,
+		OccurredAt: event.OccurredAt,
+	}
+	change.ID, _ = strconv.Atoi(id)
+	if change.Operation == OperationDelete {
+		return change, nil
+	}
+	switch data := event.Data.(type) {
+	case json.RawMessage:
+		change.Data = data
+	default:
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return Change{}, err
+		}
+		change.Data = raw
+	}
+	return change, nil
+}
+
+// NewChanges returns the changes events record.
+func NewChanges(events []domain.Event) ([]Change, error) {
+	changes := make([]Change, 0, len(events))
+	for _, event := range events {
+		change, err := NewChange(event)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func operation(eventType string) string {
+	switch eventType {
+	case domain.EventBookCreated, domain.EventAuthorCreated:
+		return OperationInsert
+	case domain.EventBookDeleted, domain.EventAuthorDeleted:
+		return OperationDelete
+	case domain.EventAuthorBooksChanged:
+		return OperationLink
+	}
+	return OperationUpdate
+}
@@ -0,0 +1,24 @@
+// Package v1 holds the request and response representations of version 1
+// of the HTTP API and the mappers from the domain models. Handlers bind and
+// render these types only, so persistence changes do not reach clients.
+package v1
+
+// MaxDepth bounds Mapper.Depth, as every level is another preload.
+const MaxDepth = 3
+
+// Mapper converts domain models into v1 representations. A resource always
+// lists its direct associations as summaries; Depth is the number of
+// association levels rendered, counting that first one.
+type Mapper struct {
+	Depth int
+}
+
+func (m Mapper) depth() int {
+	switch {
+	case m.Depth < 1:
+		return 1
+	case m.Depth > MaxDepth:
+		return MaxDepth
+	}
+	return m.Depth
+}
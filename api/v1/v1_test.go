@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"encoding/json"
+	"geniuscrew/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper(t *testing.T) {
+	as := assert.New(t)
+	book := domain.Book{ID: 1, Title: "Go", ISBN: "978160309028", Authors: []domain.Author{{
+		ID:             7,
+		Name:           "John",
+		Email:          "john@example.com",
+		BooksPublished: []domain.Book{{ID: 1, Title: "Go"}, {ID: 2, Title: "C"}},
+	}}}
+
+	t.Run("default depth summarizes direct associations only", func(t *testing.T) {
+		out := Mapper{}.Book(book)
+		as.Len(out.Authors, 1)
+		as.Equal("John", out.Authors[0].Name)
+		as.Nil(out.Authors[0].BooksPublished)
+		data, err := json.Marshal(out)
+		as.NoError(err)
+		as.NotContains(string(data), "john@example.com")
+		as.NotContains(string(data), "books_published")
+	})
+
+	t.Run("deeper nesting renders the next level", func(t *testing.T) {
+		out := Mapper{Depth: 2}.Book(book)
+		as.Len(out.Authors[0].BooksPublished, 2)
+		as.Nil(out.Authors[0].BooksPublished[0].Authors)
+	})
+
+	t.Run("no associations render as an empty list", func(t *testing.T) {
+		out := Mapper{}.Author(domain.Author{ID: 7})
+		as.NotNil(out.BooksPublished)
+		as.Empty(out.BooksPublished)
+	})
+}
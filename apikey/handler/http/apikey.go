@@ -0,0 +1,174 @@
+package http
+
+import (
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/render"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	APIKeyService domain.APIKeyService
+}
+
+func NewAPIKeyHandler(router *gin.Engine, ks domain.APIKeyService) {
+	handler := &APIKeyHandler{
+		APIKeyService: ks,
+	}
+	api := router.Group("/api/v1/admin")
+	api.POST("/api-keys", handler.CreateAPIKey)
+	api.GET("/api-keys", handler.ListAPIKeys)
+	api.POST("/api-keys/:id/rotate", handler.RotateAPIKey)
+	api.PUT("/api-keys/:id/expiry", handler.SetAPIKeyExpiry)
+	api.DELETE("/api-keys/:id", handler.RevokeAPIKey)
+}
+
+func (p *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var input struct {
+		Name      string     `json:"name" xml:"name" validate:"gte=0,lte=100,required"`
+		Owner     string     `json:"owner" xml:"owner" validate:"gte=0,lte=100,required"`
+		Roles     []string   `json:"roles" xml:"roles>item" validate:"min=1,dive,required"`
+		Scopes    []string   `json:"scopes" xml:"scopes>item" validate:"dive,required"`
+		ExpiresAt *time.Time `json:"expires_at" xml:"expires_at"`
+	}
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	inputErr := appvalidator.InputValidator(input)
+	if inputErr != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		return
+	}
+	var ctx = c.Request.Context()
+	var key domain.APIKey
+	key.Name = input.Name
+	key.Owner = input.Owner
+	key.Roles = input.Roles
+	key.Scopes = input.Scopes
+	key.ExpiresAt = input.ExpiresAt
+	secret, err := p.APIKeyService.Create(ctx, &key)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownRole):
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": key, "secret": secret})
+}
+
+func (p *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	var ctx = c.Request.Context()
+	keys, err := p.APIKeyService.List(ctx)
+	if err != nil {
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+		return
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": keys})
+}
+
+func (p *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	var input struct {
+		GracePeriod string `json:"grace_period" xml:"grace_period"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := render.Bind(c, &input); err != nil {
+			render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	var grace time.Duration
+	if input.GracePeriod != "" {
+		grace, err = time.ParseDuration(input.GracePeriod)
+		if err != nil || grace < 0 {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "grace_period must be a non-negative duration such as 24h"})
+			return
+		}
+	}
+	var ctx = c.Request.Context()
+	key, secret, err := p.APIKeyService.Rotate(ctx, id, grace)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, domain.ErrAPIKeyInactive):
+			render.Respond(c, http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, domain.ErrUnknownRole):
+			// the policy no longer defines a role of the key
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": key, "secret": secret})
+}
+
+func (p *APIKeyHandler) SetAPIKeyExpiry(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	var input struct {
+		ExpiresAt *time.Time `json:"expires_at" xml:"expires_at"`
+	}
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	var ctx = c.Request.Context()
+	key, err := p.APIKeyService.SetExpiry(ctx, id, input.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": key})
+}
+
+func (p *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	var ctx = c.Request.Context()
+	err = p.APIKeyService.Revoke(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
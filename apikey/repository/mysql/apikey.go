@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type mysqlAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewMySqlAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return &mysqlAPIKeyRepository{db}
+}
+
+func (m *mysqlAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	err := txn.DB(ctx, m.db).Create(key).Error
+	return err
+}
+
+func (m *mysqlAPIKeyRepository) Get(ctx context.Context, id string) (domain.APIKey, error) {
+	var key domain.APIKey
+	err := txn.DB(ctx, m.db).Where("id = ?", id).First(&key).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return domain.APIKey{}, domain.ErrRecordNotFound
+		default:
+			return domain.APIKey{}, err
+		}
+	}
+	return key, nil
+}
+
+func (m *mysqlAPIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := m.db.WithContext(ctx).Order("id").Find(&keys).Error
+	if err != nil {
+		return []domain.APIKey{}, err
+	}
+	return keys, nil
+}
+
+// Update writes the fields that may change after creation: expiry and
+// revocation.
+func (m *mysqlAPIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	err := txn.DB(ctx, m.db).Model(key).Select("ExpiresAt", "RevokedAt").Updates(key).Error
+	return err
+}
+
+func (m *mysqlAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	var key domain.APIKey
+	err := m.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return domain.APIKey{}, domain.ErrRecordNotFound
+		default:
+			return domain.APIKey{}, err
+		}
+	}
+	return key, nil
+}
+
+func (m *mysqlAPIKeyRepository) TouchLastUsed(ctx context.Context, id int, usedAt time.Time) error {
+	err := m.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+	return err
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/authz"
+	"log/slog"
+	"time"
+)
+
+type apiKeyService struct {
+	apiKeyRepository domain.APIKeyRepository
+	transactor       domain.Transactor
+	policy           *authz.Policy
+	now              func() time.Time
+}
+
+// NewAPIKeyService returns the key service. Keys may only be given the roles
+// policy defines.
+func NewAPIKeyService(k domain.APIKeyRepository, transactor domain.Transactor, policy *authz.Policy) domain.APIKeyService {
+	return &apiKeyService{apiKeyRepository: k, transactor: transactor, policy: policy, now: time.Now}
+}
+
+func (p *apiKeyService) Create(ctx context.Context, key *domain.APIKey) (string, error) {
+	for _, role := range key.Roles {
+		if !p.policy.Defines(role) {
+			return "", fmt.Errorf("%w: %s", domain.ErrUnknownRole, role)
+		}
+	}
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	key.Prefix = prefix
+	key.Hash = hash
+	err = p.apiKeyRepository.Create(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	slog.InfoContext(ctx, "api key created", "layer", "service", "actor", auth.Subject(ctx), "key_id", key.Prefix, "owner", key.Owner)
+	return secret, nil
+}
+
+func (p *apiKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := p.apiKeyRepository.List(ctx)
+	return keys, err
+}
+
+// Rotate creates the replacement and cuts the old key's life short in one
+// transaction, so that a failure leaves neither two open-ended keys nor none.
+func (p *apiKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (domain.APIKey, string, error) {
+	var old, replacement domain.APIKey
+	var secret string
+	err := p.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		old, err = p.apiKeyRepository.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := p.now()
+		if !old.Active(now) {
+			return domain.ErrAPIKeyInactive
+		}
+		replacement = domain.APIKey{
+			Name:      old.Name,
+			Owner:     old.Owner,
+			Roles:     old.Roles,
+			Scopes:    old.Scopes,
+			ExpiresAt: old.ExpiresAt,
+		}
+		secret, err = p.Create(ctx, &replacement)
+		if err != nil {
+			return err
+		}
+		graceEnd := now.Add(grace)
+		if old.ExpiresAt == nil || graceEnd.Before(*old.ExpiresAt) {
+			old.ExpiresAt = &graceEnd
+			return p.apiKeyRepository.Update(ctx, &old)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	slog.InfoContext(ctx, "api key rotated", "layer", "service", "actor", auth.Subject(ctx), "key_id", old.Prefix, "replaced_by", replacement.Prefix, "grace_period", grace.String())
+	return replacement, secret, nil
+}
+
+func (p *apiKeyService) SetExpiry(ctx context.Context, id string, expiresAt *time.Time) (domain.APIKey, error) {
+	key, err := p.apiKeyRepository.Get(ctx, id)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	key.ExpiresAt = expiresAt
+	err = p.apiKeyRepository.Update(ctx, &key)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	slog.InfoContext(ctx, "api key expiry set", "layer", "service", "actor", auth.Subject(ctx), "key_id", key.Prefix)
+	return key, nil
+}
+
+func (p *apiKeyService) Revoke(ctx context.Context, id string) error {
+	key, err := p.apiKeyRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	now := p.now()
+	key.RevokedAt = &now
+	err = p.apiKeyRepository.Update(ctx, &key)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "api key revoked", "layer", "service", "actor", auth.Subject(ctx), "key_id", key.Prefix)
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/authz"
+	"geniuscrew/internal/dbtest"
+	"geniuscrew/internal/txn"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newService returns the key service with the default policy, running its
+// transactions on a mocked database.
+func newService(t *testing.T, keyRepo *repository.APIKeyRepositoryMock) (domain.APIKeyService, sqlmock.Sqlmock) {
+	db, sql := dbtest.New(t)
+	return NewAPIKeyService(keyRepo, txn.NewTransactor(db), authz.DefaultPolicy()), sql
+}
+
+func TestCreate(t *testing.T) {
+	as := assert.New(t)
+	keyRepo := &repository.APIKeyRepositoryMock{}
+	t.Run("happy path: Successfully creates a key and stores only its hash", func(t *testing.T) {
+		keyRepo.On("Create", context.Background(), mock.Anything).Return(nil).Once()
+		service, _ := newService(t, keyRepo)
+		key := domain.APIKey{Name: "importer", Owner: "ops", Roles: []string{"editor"}}
+		secret, err := service.Create(context.Background(), &key)
+		as.NoError(err)
+		prefix, ok := auth.ParseAPIKey(secret)
+		as.True(ok)
+		as.Equal(prefix, key.Prefix)
+		as.True(auth.APIKeyMatches(secret, key.Hash))
+		as.NotContains(key.Hash, secret)
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: a role the policy does not define", func(t *testing.T) {
+		keyRepo := &repository.APIKeyRepositoryMock{}
+		service, _ := newService(t, keyRepo)
+		secret, err := service.Create(context.Background(), &domain.APIKey{Roles: []string{"editor", "superuser"}})
+		as.True(errors.Is(err, domain.ErrUnknownRole))
+		as.Contains(err.Error(), "superuser")
+		as.Equal("", secret)
+		keyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("system error: Database failed", func(t *testing.T) {
+		keyRepo.On("Create", context.Background(), mock.Anything).Return(errors.New("something failed")).Once()
+		service, _ := newService(t, keyRepo)
+		secret, err := service.Create(context.Background(), &domain.APIKey{})
+		as.Error(err)
+		as.Equal("", secret)
+		keyRepo.AssertExpectations(t)
+	})
+}
+
+func TestRotate(t *testing.T) {
+	as := assert.New(t)
+	keyRepo := &repository.APIKeyRepositoryMock{}
+	id := "1"
+	t.Run("happy path: old key expires after the grace period", func(t *testing.T) {
+		keyRepo.On("Get", mock.Anything, id).Return(domain.APIKey{ID: 1, Name: "importer", Owner: "ops", Prefix: "aaaa", Roles: []string{"editor"}}, nil).Once()
+		keyRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		keyRepo.On("Update", mock.MatchedBy(txn.InTx), mock.MatchedBy(func(k *domain.APIKey) bool {
+			return k.ID == 1 && k.ExpiresAt != nil && time.Until(*k.ExpiresAt) > 23*time.Hour
+		})).Return(nil).Once()
+		service, sql := newService(t, keyRepo)
+		sql.ExpectBegin()
+		sql.ExpectCommit()
+		key, secret, err := service.Rotate(context.Background(), id, 24*time.Hour)
+		as.NoError(err)
+		as.NotEqual("", secret)
+		as.Equal([]string{"editor"}, key.Roles)
+		as.NotEqual("aaaa", key.Prefix)
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("system error: the replacement is rolled back when the old key can't be updated", func(t *testing.T) {
+		keyRepo.On("Get", mock.Anything, id).Return(domain.APIKey{ID: 1, Roles: []string{"editor"}}, nil).Once()
+		keyRepo.On("Create", mock.MatchedBy(txn.InTx), mock.Anything).Return(nil).Once()
+		keyRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("something failed")).Once()
+		service, sql := newService(t, keyRepo)
+		sql.ExpectBegin()
+		sql.ExpectRollback()
+		_, secret, err := service.Rotate(context.Background(), id, time.Hour)
+		as.Error(err)
+		as.Equal("", secret)
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: revoked key can't be rotated", func(t *testing.T) {
+		revoked := time.Now().Add(-time.Hour)
+		keyRepo.On("Get", mock.Anything, id).Return(domain.APIKey{ID: 1, RevokedAt: &revoked}, nil).Once()
+		service, sql := newService(t, keyRepo)
+		sql.ExpectBegin()
+		sql.ExpectRollback()
+		_, _, err := service.Rotate(context.Background(), id, time.Hour)
+		as.True(errors.Is(err, domain.ErrAPIKeyInactive))
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: key not found", func(t *testing.T) {
+		keyRepo.On("Get", mock.Anything, id).Return(domain.APIKey{}, domain.ErrRecordNotFound).Once()
+		service, sql := newService(t, keyRepo)
+		sql.ExpectBegin()
+		sql.ExpectRollback()
+		_, _, err := service.Rotate(context.Background(), id, time.Hour)
+		as.True(errors.Is(err, domain.ErrRecordNotFound))
+		keyRepo.AssertExpectations(t)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	as := assert.New(t)
+	keyRepo := &repository.APIKeyRepositoryMock{}
+	id := "1"
+	t.Run("happy path: Successfully revokes a key", func(t *testing.T) {
+		keyRepo.On("Get", context.Background(), id).Return(domain.APIKey{ID: 1}, nil).Once()
+		keyRepo.On("Update", context.Background(), mock.MatchedBy(func(k *domain.APIKey) bool { return k.RevokedAt != nil })).Return(nil).Once()
+		service, _ := newService(t, keyRepo)
+		as.NoError(service.Revoke(context.Background(), id))
+		keyRepo.AssertExpectations(t)
+	})
+
+	t.Run("system error: Database failed", func(t *testing.T) {
+		keyRepo.On("Get", context.Background(), id).Return(domain.APIKey{ID: 1}, nil).Once()
+		keyRepo.On("Update", context.Background(), mock.Anything).Return(errors.New("something failed")).Once()
+		service, _ := newService(t, keyRepo)
+		as.Error(service.Revoke(context.Background(), id))
+		keyRepo.AssertExpectations(t)
+	})
+}
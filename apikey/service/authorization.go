@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/authz"
+	"time"
+)
+
+type authorizedAPIKeyService struct {
+	next   domain.APIKeyService
+	policy *authz.Policy
+}
+
+// NewAuthorizedAPIKeyService wraps k so that every method requires the
+// apikeys:manage permission.
+func NewAuthorizedAPIKeyService(k domain.APIKeyService, policy *authz.Policy) domain.APIKeyService {
+	return &authorizedAPIKeyService{next: k, policy: policy}
+}
+
+func (a *authorizedAPIKeyService) Create(ctx context.Context, key *domain.APIKey) (string, error) {
+	if err := a.policy.Authorize(ctx, authz.APIKeysManage); err != nil {
+		return "", err
+	}
+	return a.next.Create(ctx, key)
+}
+
+func (a *authorizedAPIKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	if err := a.policy.Authorize(ctx, authz.APIKeysManage); err != nil {
+		return nil, err
+	}
+	return a.next.List(ctx)
+}
+
+func (a *authorizedAPIKeyService) Rotate(ctx context.Context, id string, grace time.Duration) (domain.APIKey, string, error) {
+	if err := a.policy.Authorize(ctx, authz.APIKeysManage); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return a.next.Rotate(ctx, id, grace)
+}
+
+func (a *authorizedAPIKeyService) SetExpiry(ctx context.Context, id string, expiresAt *time.Time) (domain.APIKey, error) {
+	if err := a.policy.Authorize(ctx, authz.APIKeysManage); err != nil {
+		return domain.APIKey{}, err
+	}
+	return a.next.SetExpiry(ctx, id, expiresAt)
+}
+
+func (a *authorizedAPIKeyService) Revoke(ctx context.Context, id string) error {
+	if err := a.policy.Authorize(ctx, authz.APIKeysManage); err != nil {
+		return err
+	}
+	return a.next.Revoke(ctx, id)
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	catalogv1 "geniuscrew/api/proto/catalog/v1"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/rpc"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// authorFields maps the searchable fields to the filter of AuthorService.
+var authorFields = map[catalogv1.AuthorField]string{
+	catalogv1.AuthorField_AUTHOR_FIELD_NAME:    "name",
+	catalogv1.AuthorField_AUTHOR_FIELD_SURNAME: "surname",
+	catalogv1.AuthorField_AUTHOR_FIELD_EMAIL:   "email",
+}
+
+type AuthorServer struct {
+	catalogv1.UnimplementedAuthorServiceServer
+	AuthorService domain.AuthorService
+	Mapper        v1.Mapper
+}
+
+func NewAuthorServer(server *grpc.Server, as domain.AuthorService, mapper v1.Mapper) {
+	catalogv1.RegisterAuthorServiceServer(server, &AuthorServer{
+		AuthorService: as,
+		Mapper:        mapper,
+	})
+}
+
+func (p *AuthorServer) CreateAuthor(ctx context.Context, req *catalogv1.CreateAuthorRequest) (*catalogv1.Author, error) {
+	input := v1.CreateAuthorRequest{
+		Name:           req.GetName(),
+		Surname:        req.GetSurname(),
+		Email:          req.GetEmail(),
+		BooksPublished: req.GetBookIsbns(),
+	}
+	if inputErr := appvalidator.InputValidator(input); inputErr != nil {
+		return nil, rpc.InvalidArgument(inputErr)
+	}
+	author := input.Author()
+	if err := p.AuthorService.Create(ctx, input.BooksPublished, &author); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromAuthor(p.Mapper.Author(author)), nil
+}
+
+func (p *AuthorServer) GetAuthor(ctx context.Context, req *catalogv1.GetAuthorRequest) (*catalogv1.Author, error) {
+	id, err := authorID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	author, err := p.AuthorService.Get(ctx, id, domain.QueryOptions{Depth: p.Mapper.Depth})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromAuthor(p.Mapper.Author(author)), nil
+}
+
+func (p *AuthorServer) BatchGetAuthors(ctx context.Context, req *catalogv1.BatchGetAuthorsRequest) (*catalogv1.BatchGetAuthorsResponse, error) {
+	ids, err := rpc.IDs(req.GetIds())
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &catalogv1.BatchGetAuthorsResponse{}, nil
+	}
+	authors, err := p.AuthorService.GetByIDs(ctx, ids, domain.QueryOptions{Depth: p.Mapper.Depth})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &catalogv1.BatchGetAuthorsResponse{Authors: catalogv1.FromAuthors(p.Mapper.Authors(authors))}, nil
+}
+
+func (p *AuthorServer) SearchAuthors(ctx context.Context, req *catalogv1.SearchAuthorsRequest) (*catalogv1.SearchAuthorsResponse, error) {
+	filter, ok := authorFields[req.GetField()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "field must be one of AUTHOR_FIELD_NAME, AUTHOR_FIELD_SURNAME, AUTHOR_FIELD_EMAIL")
+	}
+	limit, offset, err := rpc.Page(req.GetLimit(), req.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+	authors, err := p.AuthorService.GetByFilter(ctx, filter, req.GetValue(), domain.QueryOptions{Depth: p.Mapper.Depth, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &catalogv1.SearchAuthorsResponse{Authors: catalogv1.FromAuthors(p.Mapper.Authors(authors))}, nil
+}
+
+func (p *AuthorServer) UpdateAuthor(ctx context.Context, req *catalogv1.UpdateAuthorRequest) (*catalogv1.Author, error) {
+	input := v1.UpdateAuthorRequest{
+		Name:           req.GetName(),
+		Surname:        req.GetSurname(),
+		Email:          req.GetEmail(),
+		BooksPublished: req.GetBookIsbns(),
+	}
+	return p.update(ctx, req.GetId(), input)
+}
+
+func (p *AuthorServer) SetAuthorBooks(ctx context.Context, req *catalogv1.SetAuthorBooksRequest) (*catalogv1.Author, error) {
+	return p.update(ctx, req.GetAuthorId(), v1.UpdateAuthorRequest{BooksPublished: req.GetBookIsbns()})
+}
+
+func (p *AuthorServer) update(ctx context.Context, rawID int64, input v1.UpdateAuthorRequest) (*catalogv1.Author, error) {
+	id, err := authorID(rawID)
+	if err != nil {
+		return nil, err
+	}
+	if inputErr := appvalidator.InputValidator(input); inputErr != nil {
+		return nil, rpc.InvalidArgument(inputErr)
+	}
+	author, err := p.AuthorService.Get(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	if err := p.AuthorService.Update(ctx, id, &author, input.Author(), input.BooksPublished); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	updated, err := p.AuthorService.Get(ctx, id, domain.QueryOptions{Depth: p.Mapper.Depth})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromAuthor(p.Mapper.Author(updated)), nil
+}
+
+func (p *AuthorServer) DeleteAuthor(ctx context.Context, req *catalogv1.DeleteAuthorRequest) (*emptypb.Empty, error) {
+	id, err := authorID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	var author domain.Author
+	if err := p.AuthorService.Delete(ctx, id, &author); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// authorID returns id in the form the service takes it.
+func authorID(id int64) (string, error) {
+	if id < 1 {
+		return "", status.Error(codes.InvalidArgument, "invalid id parameter")
+	}
+	return strconv.FormatInt(id, 10), nil
+}
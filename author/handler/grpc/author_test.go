@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	catalogv1 "geniuscrew/api/proto/catalog/v1"
+	v1 "geniuscrew/api/v1"
+	_authorService "geniuscrew/author/service"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/ratelimit"
+	"geniuscrew/internal/rpc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves the author service over an in-memory listener the way
+// cmd/api does and returns a client connection to it.
+func dial(t *testing.T, authorRepo *repository.AuthorRepositoryMock) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	server, _ := rpc.NewServer(auth.NewAuthenticator(nil, &repository.APIKeyRepositoryMock{}), ratelimit.NewMemoryStore(), nil, time.Second)
+	service := _authorService.NewAuthorService(authorRepo, &repository.AuthorBooksRepositoryMock{}, &repository.BookRepositoryMock{})
+	NewAuthorServer(server, service, v1.Mapper{Depth: 1})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGetAuthor(t *testing.T) {
+	as := assert.New(t)
+	authorRepo := &repository.AuthorRepositoryMock{}
+	authorRepo.On("Get", mock.Anything, "7", domain.QueryOptions{Depth: 1}).Return(domain.Author{
+		ID:             7,
+		Name:           "Alan",
+		Email:          "alan@example.com",
+		BooksPublished: []domain.Book{{ID: 1, Title: "Go"}},
+	}, nil).Once()
+	authorRepo.On("Get", mock.Anything, "8", domain.QueryOptions{Depth: 1}).Return(domain.Author{}, domain.ErrRecordNotFound).Once()
+	client := catalogv1.NewAuthorServiceClient(dial(t, authorRepo))
+
+	author, err := client.GetAuthor(context.Background(), &catalogv1.GetAuthorRequest{Id: 7})
+	as.NoError(err)
+	as.Equal("Alan", author.GetName())
+	as.Equal("alan@example.com", author.GetEmail())
+	as.Len(author.GetBooksPublished(), 1)
+	as.Equal("Go", author.GetBooksPublished()[0].GetTitle())
+
+	_, err = client.GetAuthor(context.Background(), &catalogv1.GetAuthorRequest{Id: 8})
+	as.Equal(codes.NotFound, status.Code(err))
+
+	_, err = client.GetAuthor(context.Background(), &catalogv1.GetAuthorRequest{})
+	as.Equal(codes.InvalidArgument, status.Code(err))
+	authorRepo.AssertExpectations(t)
+}
+
+func TestBatchGetAuthors(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: returns the authors found", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorRepo.On("GetByIDs", mock.Anything, []int{7, 8}, domain.QueryOptions{Depth: 1}).Return([]domain.Author{{ID: 7, Name: "Alan"}}, nil).Once()
+		client := catalogv1.NewAuthorServiceClient(dial(t, authorRepo))
+
+		res, err := client.BatchGetAuthors(context.Background(), &catalogv1.BatchGetAuthorsRequest{Ids: []int64{7, 8}})
+		as.NoError(err)
+		as.Len(res.GetAuthors(), 1)
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: more ids than a page", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		client := catalogv1.NewAuthorServiceClient(dial(t, authorRepo))
+		ids := make([]int64, domain.MaxLimit+1)
+		for i := range ids {
+			ids[i] = int64(i + 1)
+		}
+
+		_, err := client.BatchGetAuthors(context.Background(), &catalogv1.BatchGetAuthorsRequest{Ids: ids})
+		as.Equal(codes.InvalidArgument, status.Code(err))
+		authorRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSearchAuthors(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: pages the search", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorRepo.On("GetByFilter", mock.Anything, "name", "Alan", domain.QueryOptions{Depth: 1, Limit: 10, Offset: 20}).Return([]domain.Author{{ID: 7, Name: "Alan"}}, nil).Once()
+		client := catalogv1.NewAuthorServiceClient(dial(t, authorRepo))
+
+		res, err := client.SearchAuthors(context.Background(), &catalogv1.SearchAuthorsRequest{
+			Field: catalogv1.AuthorField_AUTHOR_FIELD_NAME, Value: "Alan", Limit: 10, Offset: 20,
+		})
+		as.NoError(err)
+		as.Len(res.GetAuthors(), 1)
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("happy path: an unset limit is the largest page", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorRepo.On("GetByFilter", mock.Anything, "email", "alan@example.com", domain.QueryOptions{Depth: 1, Limit: domain.MaxLimit}).Return([]domain.Author{}, nil).Once()
+		client := catalogv1.NewAuthorServiceClient(dial(t, authorRepo))
+
+		_, err := client.SearchAuthors(context.Background(), &catalogv1.SearchAuthorsRequest{
+			Field: catalogv1.AuthorField_AUTHOR_FIELD_EMAIL, Value: "alan@example.com",
+		})
+		as.NoError(err)
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: limit over the largest page", func(t *testing.T) {
+		client := catalogv1.NewAuthorServiceClient(dial(t, &repository.AuthorRepositoryMock{}))
+
+		_, err := client.SearchAuthors(context.Background(), &catalogv1.SearchAuthorsRequest{
+			Field: catalogv1.AuthorField_AUTHOR_FIELD_NAME, Value: "Alan", Limit: domain.MaxLimit + 1,
+		})
+		as.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestAuthorWritesRequireCredentials(t *testing.T) {
+	as := assert.New(t)
+	client := catalogv1.NewAuthorServiceClient(dial(t, &repository.AuthorRepositoryMock{}))
+
+	_, err := client.DeleteAuthor(context.Background(), &catalogv1.DeleteAuthorRequest{Id: 7})
+	as.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.SetAuthorBooks(context.Background(), &catalogv1.SetAuthorBooksRequest{AuthorId: 7, BookIsbns: []string{"978160309028"}})
+	as.Equal(codes.Unauthenticated, status.Code(err))
+}
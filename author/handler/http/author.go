@@ -1,11 +1,13 @@
 package http
 
 import (
-	"context"
 	"errors"
+	v1 "geniuscrew/api/v1"
 	"geniuscrew/domain"
 	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/conditional"
 	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/render"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,11 +15,13 @@ import (
 
 type AuthorHandler struct {
 	AuthorService domain.AuthorService
+	Mapper        v1.Mapper
 }
 
-func NewAuthorHandler(router *gin.Engine, as domain.AuthorService) {
+func NewAuthorHandler(router *gin.Engine, as domain.AuthorService, mapper v1.Mapper) {
 	handler := &AuthorHandler{
 		AuthorService: as,
+		Mapper:        mapper,
 	}
 	api := router.Group("/api/v1")
 	api.POST("/authors", handler.CreateAuthor)
@@ -28,64 +32,82 @@ func NewAuthorHandler(router *gin.Engine, as domain.AuthorService) {
 }
 
 func (p *AuthorHandler) CreateAuthor(c *gin.Context) {
-	var input struct {
-		Name           string   `json:"name" validate:"gte=0,lte=500,required"`
-		Surname        string   `json:"surname" validate:"gte=0,lte=500,required"`
-		Email          string   `json:"email" validate:"email,required"`
-		BooksPublished []string `json:"books_published" validate:"min=1,dive,required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input v1.CreateAuthorRequest
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	inputErr := appvalidator.InputValidator(input)
 	if inputErr != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
 		return
 	}
-	var ctx = context.TODO()
-	var author domain.Author
-	author.Name = input.Name
-	author.Surname = input.Surname
-	author.Email = input.Email
+	var ctx = c.Request.Context()
+	author := input.Author()
 	err := p.AuthorService.Create(ctx, input.BooksPublished, &author)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrDuplicateRecord):
-			c.JSON(http.StatusConflict, gin.H{"error": "Author Email is registered"})
+			render.Respond(c, http.StatusConflict, gin.H{"error": "Author Email is registered"})
 			return
 		case errors.Is(err, domain.ErrBookNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"payload": author})
+	render.Respond(c, http.StatusOK, gin.H{"payload": p.Mapper.Author(author)})
 }
 
 func (p *AuthorHandler) GetAuthorByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := domain.ParseQueryOptions(c.Query("fields"), c.Query("include"), domain.AuthorColumns, domain.AuthorAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var ctx = context.TODO()
-	author, err := p.AuthorService.Get(ctx, id)
+	opts.Depth = p.Mapper.Depth
+	var ctx = c.Request.Context()
+	author, err := p.AuthorService.Get(ctx, id, opts)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrRecordNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusFound, gin.H{"payload": author})
+	payload, err := helpers.Sparse(p.Mapper.Author(author), opts, domain.AuthorAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := gin.H{"payload": payload}
+	format := render.Format(c, response)
+	if format == "" {
+		render.NotAcceptable(c, response)
+		return
+	}
+	etag, err := conditional.ETag(format, payload)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if conditional.NotModified(c, etag, author.LastModified()) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	render.Respond(c, http.StatusFound, response)
 }
 
 func (p *AuthorHandler) GetByFilter(c *gin.Context) {
@@ -95,73 +117,80 @@ func (p *AuthorHandler) GetByFilter(c *gin.Context) {
 	if !helpers.In(filter, filterSafeList...) {
 		message := make(map[string][]string)
 		message["filter_fields_allowed"] = filterSafeList
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": message})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": message})
 		return
 	}
-	var ctx = context.TODO()
-
-	author, err := p.AuthorService.GetByFilter(ctx, filter, filterValue)
-	if len(author) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "no author matches"})
+	opts, err := domain.ParseQueryOptions(c.Query("fields"), c.Query("include"), domain.AuthorColumns, domain.AuthorAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
+	opts.Limit, opts.Offset, err = domain.ParsePage(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Depth = p.Mapper.Depth
+	var ctx = c.Request.Context()
+
+	author, err := p.AuthorService.GetByFilter(ctx, filter, filterValue, opts)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrRecordNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusFound, gin.H{"payload": author})
+	if len(author) == 0 {
+		render.Respond(c, http.StatusNotFound, gin.H{"error": "no author matches"})
+		return
+	}
+	payload, err := helpers.Sparse(p.Mapper.Authors(author), opts, domain.AuthorAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	render.Respond(c, http.StatusFound, gin.H{"payload": payload})
 }
 
 func (p *AuthorHandler) UpdateAuthorByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var input struct {
-		Name           string   `json:"name" validate:"isdefault|gte=0,lte=500"`
-		Surname        string   `json:"surname" validate:"isdefault|gte=0,lte=500"`
-		Email          string   `json:"email" validate:"isdefault|email"`
-		BooksPublished []string `json:"books_published" validate:"isdefault|min=1,dive,required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input v1.UpdateAuthorRequest
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	inputErr := appvalidator.InputValidator(input)
 	if inputErr != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
 		return
 	}
-	var ctx = context.TODO()
-	author, err := p.AuthorService.Get(ctx, id)
+	var ctx = c.Request.Context()
+	author, err := p.AuthorService.Get(ctx, id, domain.QueryOptions{})
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrRecordNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	var updatedAuthor domain.Author
-	updatedAuthor.Email = input.Email
-	updatedAuthor.Name = input.Name
-	updatedAuthor.Surname = input.Surname
-	err = p.AuthorService.Update(ctx, id, &author, updatedAuthor, input.BooksPublished)
+	err = p.AuthorService.Update(ctx, id, &author, input.Author(), input.BooksPublished)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
+	render.Respond(c, http.StatusOK, gin.H{
 		"message": "author profile updated",
 	})
 }
@@ -170,15 +199,15 @@ func (p *AuthorHandler) DeleteAuthorByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var ctx = context.TODO()
+	var ctx = c.Request.Context()
 	var author domain.Author
 	err = p.AuthorService.Delete(ctx, id, &author)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Author deleted successfully"})
+	render.Respond(c, http.StatusOK, gin.H{"message": "Author deleted successfully"})
 }
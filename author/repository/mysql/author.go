@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
 
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type mysqlAuthorRepository struct {
@@ -19,13 +19,13 @@ func NewMySqlAuthorRepository(db *gorm.DB) domain.AuthorRepository {
 }
 
 func (m *mysqlAuthorRepository) Create(ctx context.Context, author *domain.Author) error {
-	err := m.db.WithContext(ctx).Create(author).Error
+	err := txn.DB(ctx, m.db).Create(author).Error
 	return err
 }
 
-func (m *mysqlAuthorRepository) Get(ctx context.Context, id string) (domain.Author, error) {
+func (m *mysqlAuthorRepository) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
 	var author domain.Author
-	err := m.db.WithContext(ctx).Preload(clause.Associations).Where("id = ?", id).First(&author).Error
+	err := m.query(ctx, opts).Where("id = ?", id).First(&author).Error
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
@@ -38,21 +38,51 @@ func (m *mysqlAuthorRepository) Get(ctx context.Context, id string) (domain.Auth
 }
 
 func (m *mysqlAuthorRepository) Update(ctx context.Context, author *domain.Author, updatedAuthor domain.Author) error {
-	err := m.db.WithContext(ctx).Model(author).Updates(updatedAuthor).Error
+	err := txn.DB(ctx, m.db).Model(author).Updates(updatedAuthor).Error
 	return err
 }
 
 func (m *mysqlAuthorRepository) Delete(ctx context.Context, id string, author *domain.Author) error {
-	err := m.db.WithContext(ctx).Select("Book").Where("id = ?", id).Delete(author).Error
+	err := txn.DB(ctx, m.db).Select("Book").Where("id = ?", id).Delete(author).Error
 	return err
 }
 
-func (m *mysqlAuthorRepository) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Author, error) {
+func (m *mysqlAuthorRepository) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
 	var authors []domain.Author
 	filterValue = "%" + filterValue + "%"
-	err := m.db.WithContext(ctx).Preload(clause.Associations).Where(fmt.Sprintf("%s LIKE ?", filter), filterValue).Find(&authors).Error
+	err := m.query(ctx, opts).Where(fmt.Sprintf("%s LIKE ?", filter), filterValue).Find(&authors).Error
 	if err != nil {
 		return []domain.Author{}, err
 	}
 	return authors, nil
 }
+
+// GetByIDs reads the authors with the given ids in one query per level of
+// associations. Ids without a author are skipped.
+func (m *mysqlAuthorRepository) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	var authors []domain.Author
+	if len(ids) == 0 {
+		return authors, nil
+	}
+	err := m.query(ctx, opts).Where("id IN ?", ids).Find(&authors).Error
+	if err != nil {
+		return []domain.Author{}, err
+	}
+	return authors, nil
+}
+
+// query selects only the columns and preloads only the associations opts
+// asks for, and reads only the page it asks for.
+func (m *mysqlAuthorRepository) query(ctx context.Context, opts domain.QueryOptions) *gorm.DB {
+	tx := txn.DB(ctx, m.db)
+	if columns := opts.Columns(domain.AuthorColumns); columns != nil {
+		tx = tx.Select(columns)
+	}
+	if opts.Includes("books_published") {
+		tx = tx.Preload(opts.Path("BooksPublished", "Authors"))
+	}
+	if opts.Limit > 0 {
+		tx = tx.Order("id").Limit(opts.Limit).Offset(opts.Offset)
+	}
+	return tx
+}
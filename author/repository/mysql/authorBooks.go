@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,38 +20,83 @@ func NewMySqlAuthorBooksRepository(db *gorm.DB) domain.AuthorBooksRepository {
 func (m *mysqlAuthorBooksRepository) Create(ctx context.Context, author *domain.Author, authorBooks []domain.Book) error {
 	var err error
 	for _, book := range authorBooks {
-		err = m.db.WithContext(ctx).Create(&domain.AuthorBooks{
+		err = txn.DB(ctx, m.db).Create(&domain.AuthorBooks{
 			BookID:   book.ID,
 			AuthorID: author.ID,
 		}).Error
 		if err != nil {
-			m.db.Rollback()
 			return err
 		}
 	}
+	err = m.touch(ctx, nil, bookIDs(authorBooks))
 	return err
 }
 
 func (m *mysqlAuthorBooksRepository) Update(ctx context.Context, id string, author *domain.Author, authorBooks []domain.Book) error {
 	var err error
-	err = m.db.WithContext(ctx).Where("author_id = ?", id).Delete(&domain.AuthorBooks{}).Error
+	linked, err := m.linkedBookIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = txn.DB(ctx, m.db).Where("author_id = ?", id).Delete(&domain.AuthorBooks{}).Error
 	if err != nil {
 		return err
 	}
 	for _, book := range authorBooks {
-		err = m.db.WithContext(ctx).Create(&domain.AuthorBooks{
+		err = txn.DB(ctx, m.db).Create(&domain.AuthorBooks{
 			BookID:   book.ID,
 			AuthorID: author.ID,
 		}).Error
 		if err != nil {
-			m.db.Rollback()
 			return err
 		}
 	}
+	err = m.touch(ctx, []string{id}, append(linked, bookIDs(authorBooks)...))
 	return err
 }
 
 func (m *mysqlAuthorBooksRepository) Delete(ctx context.Context, id string) error {
-	err := m.db.WithContext(ctx).Where("author_id = ?", id).Delete(&domain.AuthorBooks{}).Error
+	linked, err := m.linkedBookIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = txn.DB(ctx, m.db).Where("author_id = ?", id).Delete(&domain.AuthorBooks{}).Error
+	if err != nil {
+		return err
+	}
+	err = m.touch(ctx, nil, linked)
 	return err
 }
+
+func (m *mysqlAuthorBooksRepository) linkedBookIDs(ctx context.Context, authorID string) ([]int, error) {
+	var ids []int
+	err := txn.DB(ctx, m.db).Model(&domain.AuthorBooks{}).Where("author_id = ?", authorID).Pluck("book_id", &ids).Error
+	return ids, err
+}
+
+// touch bumps updated_at on both sides of changed links, so the Last-Modified
+// date of a book or author moves forward when its associations change.
+func (m *mysqlAuthorBooksRepository) touch(ctx context.Context, authorIDs []string, bookIDs []int) error {
+	now := time.Now()
+	if len(authorIDs) > 0 {
+		err := txn.DB(ctx, m.db).Model(&domain.Author{}).Where("id IN ?", authorIDs).UpdateColumn("updated_at", now).Error
+		if err != nil {
+			return err
+		}
+	}
+	if len(bookIDs) > 0 {
+		err := txn.DB(ctx, m.db).Model(&domain.Book{}).Where("id IN ?", bookIDs).UpdateColumn("updated_at", now).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bookIDs(books []domain.Book) []int {
+	ids := make([]int, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids
+}
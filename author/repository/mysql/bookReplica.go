@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
+	"log/slog"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlBookReplica resolves books through another BookRepository, typically
+// the remote book service, and keeps a copy of the books it resolves by ISBN
+// in the authors database. The links in author_books point at that copy.
+type mysqlBookReplica struct {
+	domain.BookRepository
+	db *gorm.DB
+}
+
+func NewMySqlBookReplica(db *gorm.DB, source domain.BookRepository) domain.BookRepository {
+	return &mysqlBookReplica{BookRepository: source, db: db}
+}
+
+// GetByISBN reads the books from the source and refreshes their local copy,
+// so that an author is always linked to the book as the source last had it.
+// Within a transaction it reads the local copy only, so that the transaction
+// does not wait on the source: the lookup refreshing it runs before, see
+// NewBookResolvingAuthorService.
+func (m *mysqlBookReplica) GetByISBN(ctx context.Context, field string, filter []string) ([]domain.Book, error) {
+	if txn.InTx(ctx) {
+		var books []domain.Book
+		err := txn.DB(ctx, m.db).Where("isbn IN ?", filter).Find(&books).Error
+		if err != nil {
+			return []domain.Book{}, err
+		}
+		return books, nil
+	}
+	books, err := m.BookRepository.GetByISBN(ctx, field, filter)
+	if err != nil || len(books) == 0 {
+		return books, err
+	}
+	copies := make([]domain.Book, 0, len(books))
+	for _, book := range books {
+		book.Authors = nil
+		copies = append(copies, book)
+	}
+	err = m.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Omit(clause.Associations).Create(&copies).Error
+	if err != nil {
+		return []domain.Book{}, err
+	}
+	return books, nil
+}
+
+// remoteBooksAuthorRepository reads authors from the authors database and
+// the books linked to them from the book service, so that a book changed or
+// deleted there is shown as it is now rather than as its local copy.
+type remoteBooksAuthorRepository struct {
+	domain.AuthorRepository
+	source domain.BookRepository
+}
+
+// NewRemoteBooksAuthorRepository returns authors with their books read from
+// source. Nested authors are kept as loaded; when source cannot be reached,
+// or within a transaction, the local copies of the books are returned.
+func NewRemoteBooksAuthorRepository(authors domain.AuthorRepository, source domain.BookRepository) domain.AuthorRepository {
+	return &remoteBooksAuthorRepository{AuthorRepository: authors, source: source}
+}
+
+func (r *remoteBooksAuthorRepository) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	author, err := r.AuthorRepository.Get(ctx, id, opts)
+	if err != nil {
+		return author, err
+	}
+	authors := []domain.Author{author}
+	r.resolve(ctx, authors)
+	return authors[0], nil
+}
+
+func (r *remoteBooksAuthorRepository) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	authors, err := r.AuthorRepository.GetByFilter(ctx, filter, filterValue, opts)
+	if err != nil {
+		return authors, err
+	}
+	r.resolve(ctx, authors)
+	return authors, nil
+}
+
+func (r *remoteBooksAuthorRepository) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	authors, err := r.AuthorRepository.GetByIDs(ctx, ids, opts)
+	if err != nil {
+		return authors, err
+	}
+	r.resolve(ctx, authors)
+	return authors, nil
+}
+
+// resolve replaces the columns of every book embedded in authors, at any
+// depth, with those source has, and drops the books source no longer has.
+// Within a transaction the local copies are kept, as the replica reads them.
+func (r *remoteBooksAuthorRepository) resolve(ctx context.Context, authors []domain.Author) {
+	if txn.InTx(ctx) {
+		return
+	}
+	ids := map[int]bool{}
+	collectBookIDs(authors, ids)
+	if len(ids) == 0 {
+		return
+	}
+	list := make([]int, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	sort.Ints(list)
+	books, err := r.source.GetByIDs(ctx, list, domain.QueryOptions{Fields: bookColumns()})
+	if err != nil {
+		slog.WarnContext(ctx, "reading the books of authors from the book service failed, serving local copies", "layer", "repository", "error", err)
+		return
+	}
+	current := make(map[int]domain.Book, len(books))
+	for _, book := range books {
+		current[book.ID] = book
+	}
+	replaceBooks(authors, current)
+}
+
+func collectBookIDs(authors []domain.Author, ids map[int]bool) {
+	for _, author := range authors {
+		for _, book := range author.BooksPublished {
+			ids[book.ID] = true
+			collectBookIDs(book.Authors, ids)
+		}
+	}
+}
+
+func replaceBooks(authors []domain.Author, current map[int]domain.Book) {
+	for i := range authors {
+		if authors[i].BooksPublished == nil {
+			continue
+		}
+		kept := make([]domain.Book, 0, len(authors[i].BooksPublished))
+		for _, book := range authors[i].BooksPublished {
+			fresh, ok := current[book.ID]
+			if !ok {
+				continue
+			}
+			fresh.Authors = nil
+			if book.Authors != nil {
+				fresh.Authors = append([]domain.Author{}, book.Authors...)
+				replaceBooks(fresh.Authors, current)
+			}
+			kept = append(kept, fresh)
+		}
+		authors[i].BooksPublished = kept
+	}
+}
+
+// bookColumns names every column of a book, and no association.
+func bookColumns() []string {
+	fields := make([]string, 0, len(domain.BookColumns))
+	for field := range domain.BookColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/dbtest"
+	"geniuscrew/internal/txn"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoteBooksAuthorRepository(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	// the local copies: book 2 was deleted and book 1 renamed by the book service
+	author := domain.Author{ID: 7, Name: "John", BooksPublished: []domain.Book{
+		{ID: 1, Title: "Old", Authors: []domain.Author{{ID: 8, Name: "Jane", BooksPublished: []domain.Book{{ID: 2, Title: "Gone"}}}}},
+		{ID: 2, Title: "Gone"},
+	}}
+
+	t.Run("happy path: shows the books as the book service has them", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		authorRepo.On("Get", ctx, "7", domain.QueryOptions{}).Return(author, nil).Once()
+		bookRepo.On("GetByIDs", ctx, []int{1, 2}, mock.Anything).Return([]domain.Book{{ID: 1, Title: "New"}}, nil).Once()
+
+		got, err := NewRemoteBooksAuthorRepository(authorRepo, bookRepo).Get(ctx, "7", domain.QueryOptions{})
+		as.NoError(err)
+		as.Len(got.BooksPublished, 1)
+		as.Equal("New", got.BooksPublished[0].Title)
+		// nested authors are kept, with their books resolved too
+		as.Equal("Jane", got.BooksPublished[0].Authors[0].Name)
+		as.Empty(got.BooksPublished[0].Authors[0].BooksPublished)
+		bookRepo.AssertExpectations(t)
+	})
+
+	t.Run("book service down: serves the local copies", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		authorRepo.On("GetByIDs", ctx, []int{7}, domain.QueryOptions{}).Return([]domain.Author{author}, nil).Once()
+		bookRepo.On("GetByIDs", ctx, []int{1, 2}, mock.Anything).Return([]domain.Book{}, domain.ErrUnavailable).Once()
+
+		got, err := NewRemoteBooksAuthorRepository(authorRepo, bookRepo).GetByIDs(ctx, []int{7}, domain.QueryOptions{})
+		as.NoError(err)
+		as.Equal(author, got[0])
+	})
+
+	t.Run("in a transaction: does not call the book service", func(t *testing.T) {
+		db, sql := dbtest.New(t)
+		sql.ExpectBegin()
+		sql.ExpectCommit()
+		authorRepo := &repository.AuthorRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		authorRepo.On("Get", mock.Anything, "7", domain.QueryOptions{}).Return(author, nil).Once()
+		repo := NewRemoteBooksAuthorRepository(authorRepo, bookRepo)
+
+		err := txn.NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+			got, err := repo.Get(ctx, "7", domain.QueryOptions{})
+			as.Equal(author, got)
+			return err
+		})
+		as.NoError(err)
+		bookRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestBookReplicaGetByISBN(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	t.Run("happy path: refreshes the local copy from the source", func(t *testing.T) {
+		db, sql := dbtest.New(t)
+		sql.ExpectBegin()
+		sql.ExpectExec("INSERT INTO `books` .* ON DUPLICATE KEY UPDATE").WillReturnResult(sqlmock.NewResult(1, 1))
+		sql.ExpectCommit()
+		bookRepo := &repository.BookRepositoryMock{}
+		bookRepo.On("GetByISBN", ctx, "ISBN", []string{"978160309028"}).Return([]domain.Book{{ID: 1, ISBN: "978160309028"}}, nil).Once()
+
+		books, err := NewMySqlBookReplica(db, bookRepo).GetByISBN(ctx, "ISBN", []string{"978160309028"})
+		as.NoError(err)
+		as.Len(books, 1)
+		bookRepo.AssertExpectations(t)
+	})
+
+	t.Run("in a transaction: reads the local copy only", func(t *testing.T) {
+		db, sql := dbtest.New(t)
+		sql.ExpectBegin()
+		sql.ExpectQuery("SELECT \\* FROM `books` WHERE isbn IN").WillReturnRows(sqlmock.NewRows([]string{"id", "isbn"}).AddRow(1, "978160309028"))
+		sql.ExpectCommit()
+		bookRepo := &repository.BookRepositoryMock{}
+		replica := NewMySqlBookReplica(db, bookRepo)
+
+		err := txn.NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+			books, err := replica.GetByISBN(ctx, "ISBN", []string{"978160309028"})
+			as.Len(books, 1)
+			return err
+		})
+		as.NoError(err)
+		bookRepo.AssertNotCalled(t, "GetByISBN", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
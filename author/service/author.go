@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/metrics"
+	"log/slog"
 	"strings"
 )
 
@@ -35,20 +38,29 @@ func (p *authorService) Create(ctx context.Context, books []string, author *doma
 	if err != nil {
 		return err
 	}
-	return err
+	author.BooksPublished = authorBooks
+	metrics.AuthorsCreated.Inc()
+	metrics.AuthorLinksChanged.WithLabelValues("create").Inc()
+	slog.InfoContext(ctx, "author created", "layer", "service", "actor", auth.Subject(ctx), "author_id", author.ID, "books", len(authorBooks))
+	return nil
 }
 
-func (p *authorService) Get(ctx context.Context, id string) (domain.Author, error) {
-	author, err := p.authorRepository.Get(ctx, id)
+func (p *authorService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	author, err := p.authorRepository.Get(ctx, id, opts)
 	return author, err
 }
 
-func (p *authorService) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Author, error) {
+func (p *authorService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
 
-	author, err := p.authorRepository.GetByFilter(ctx, filter, filterValue)
+	author, err := p.authorRepository.GetByFilter(ctx, filter, filterValue, opts)
+	slog.DebugContext(ctx, "author search", "layer", "service", "filter", filter, "matches", len(author))
 	return author, err
 }
 
+func (p *authorService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	return p.authorRepository.GetByIDs(ctx, ids, opts)
+}
+
 func (p *authorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
 	var authorBooks []domain.Book
 	var err error
@@ -69,7 +81,10 @@ func (p *authorService) Update(ctx context.Context, id string, author *domain.Au
 	if err != nil {
 		return err
 	}
-	return err
+	author.BooksPublished = authorBooks
+	metrics.AuthorLinksChanged.WithLabelValues("update").Inc()
+	slog.InfoContext(ctx, "author updated", "layer", "service", "actor", auth.Subject(ctx), "author_id", id, "books", len(authorBooks))
+	return nil
 }
 
 func (p *authorService) Delete(ctx context.Context, id string, author *domain.Author) error {
@@ -78,5 +93,10 @@ func (p *authorService) Delete(ctx context.Context, id string, author *domain.Au
 		return err
 	}
 	err = p.authorRepository.Delete(ctx, id, author)
-	return err
+	if err != nil {
+		return err
+	}
+	metrics.AuthorLinksChanged.WithLabelValues("delete").Inc()
+	slog.InfoContext(ctx, "author deleted", "layer", "service", "actor", auth.Subject(ctx), "author_id", id)
+	return nil
 }
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"geniuscrew/domain"
 	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/metrics"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -104,11 +106,11 @@ func TestGet(t *testing.T) {
 	authorBookRepo := &repository.AuthorBooksRepositoryMock{}
 	id := "1"
 	t.Run("happy path: Successfully fetches an author", func(t *testing.T) {
-		authorRepo.On("Get", context.Background(), id).Return(domain.Author{
+		authorRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Author{
 			Name: "John Doe",
 		}, nil).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		author, err := service.Get(context.Background(), id)
+		author, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.NoError(err)
 		as.Equal("John Doe", author.Name)
 		authorBookRepo.AssertExpectations(t)
@@ -117,9 +119,9 @@ func TestGet(t *testing.T) {
 	})
 
 	t.Run("input error: author not found", func(t *testing.T) {
-		authorRepo.On("Get", context.Background(), id).Return(domain.Author{}, domain.ErrRecordNotFound).Once()
+		authorRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Author{}, domain.ErrRecordNotFound).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		author, err := service.Get(context.Background(), id)
+		author, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal("", author.Name)
 		authorBookRepo.AssertExpectations(t)
@@ -128,9 +130,9 @@ func TestGet(t *testing.T) {
 	})
 
 	t.Run("system error: Database failed", func(t *testing.T) {
-		authorRepo.On("Get", context.Background(), id).Return(domain.Author{}, errors.New("something failed")).Once()
+		authorRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Author{}, errors.New("something failed")).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		author, err := service.Get(context.Background(), id)
+		author, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal("", author.Name)
 		authorBookRepo.AssertExpectations(t)
@@ -146,7 +148,7 @@ func TestGetByFilter(t *testing.T) {
 	authorBookRepo := &repository.AuthorBooksRepositoryMock{}
 
 	t.Run("happy path: Successfully fetches an author by filter", func(t *testing.T) {
-		authorRepo.On("GetByFilter", context.Background(), "name", "john").Return([]domain.Author{
+		authorRepo.On("GetByFilter", context.Background(), "name", "john", domain.QueryOptions{}).Return([]domain.Author{
 			{
 				Name: "John Doe",
 			},
@@ -155,7 +157,7 @@ func TestGetByFilter(t *testing.T) {
 			},
 		}, nil).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		authors, err := service.GetByFilter(context.Background(), "name", "john")
+		authors, err := service.GetByFilter(context.Background(), "name", "john", domain.QueryOptions{})
 		as.NoError(err)
 		as.Equal(len(authors), 2)
 		authorBookRepo.AssertExpectations(t)
@@ -164,9 +166,9 @@ func TestGetByFilter(t *testing.T) {
 	})
 
 	t.Run("input error: filter doesn't match record", func(t *testing.T) {
-		authorRepo.On("GetByFilter", context.Background(), "name", "dgdfhdhj").Return([]domain.Author{}, domain.ErrRecordNotFound).Once()
+		authorRepo.On("GetByFilter", context.Background(), "name", "dgdfhdhj", domain.QueryOptions{}).Return([]domain.Author{}, domain.ErrRecordNotFound).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		authors, err := service.GetByFilter(context.Background(), "name", "dgdfhdhj")
+		authors, err := service.GetByFilter(context.Background(), "name", "dgdfhdhj", domain.QueryOptions{})
 		as.Error(err)
 		as.Equal(len(authors), 0)
 		authorBookRepo.AssertExpectations(t)
@@ -175,9 +177,9 @@ func TestGetByFilter(t *testing.T) {
 	})
 
 	t.Run("system error: Database failed", func(t *testing.T) {
-		authorRepo.On("GetByFilter", context.Background(), "name", "dgdfhdhj").Return([]domain.Author{}, errors.New("something failed")).Once()
+		authorRepo.On("GetByFilter", context.Background(), "name", "dgdfhdhj", domain.QueryOptions{}).Return([]domain.Author{}, errors.New("something failed")).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
-		authors, err := service.GetByFilter(context.Background(), "name", "dgdfhdhj")
+		authors, err := service.GetByFilter(context.Background(), "name", "dgdfhdhj", domain.QueryOptions{})
 		as.Error(err)
 		as.Equal(len(authors), 0)
 		authorBookRepo.AssertExpectations(t)
@@ -280,12 +282,15 @@ func TestDelete(t *testing.T) {
 	authorRepo := &repository.AuthorRepositoryMock{}
 	bookRepo := &repository.BookRepositoryMock{}
 	authorBookRepo := &repository.AuthorBooksRepositoryMock{}
+	deletes := metrics.AuthorLinksChanged.WithLabelValues("delete")
 	t.Run("happy path: successfully deletes an author", func(t *testing.T) {
 		authorBookRepo.On("Delete", context.Background(), id).Return(nil).Once()
 		authorRepo.On("Delete", context.Background(), id, &domain.Author{}).Return(nil).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
+		before := testutil.ToFloat64(deletes)
 		err := service.Delete(context.Background(), id, &domain.Author{})
 		as.NoError(err)
+		as.Equal(before+1, testutil.ToFloat64(deletes))
 		authorBookRepo.AssertExpectations(t)
 		bookRepo.AssertExpectations(t)
 		authorRepo.AssertExpectations(t)
@@ -303,8 +308,11 @@ func TestDelete(t *testing.T) {
 		authorBookRepo.On("Delete", context.Background(), id).Return(nil).Once()
 		authorRepo.On("Delete", context.Background(), id, &domain.Author{}).Return(errors.New("an error occured")).Once()
 		service := NewAuthorService(authorRepo, authorBookRepo, bookRepo)
+		before := testutil.ToFloat64(deletes)
 		err := service.Delete(context.Background(), id, &domain.Author{})
 		as.Error(err)
+		// the links were not changed: the delete failed
+		as.Equal(before, testutil.ToFloat64(deletes))
 		authorBookRepo.AssertExpectations(t)
 		bookRepo.AssertExpectations(t)
 		authorRepo.AssertExpectations(t)
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/authz"
+)
+
+type authorizedAuthorService struct {
+	next   domain.AuthorService
+	policy *authz.Policy
+}
+
+// NewAuthorizedAuthorService wraps a so that every method first checks the
+// caller's permission against policy.
+func NewAuthorizedAuthorService(a domain.AuthorService, policy *authz.Policy) domain.AuthorService {
+	return &authorizedAuthorService{next: a, policy: policy}
+}
+
+func (a *authorizedAuthorService) Create(ctx context.Context, books []string, author *domain.Author) error {
+	if err := a.policy.Authorize(ctx, authz.AuthorsCreate); err != nil {
+		return err
+	}
+	return a.next.Create(ctx, books, author)
+}
+
+func (a *authorizedAuthorService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	if err := a.policy.Authorize(ctx, authz.AuthorsRead); err != nil {
+		return domain.Author{}, err
+	}
+	return a.next.Get(ctx, id, opts)
+}
+
+func (a *authorizedAuthorService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	if err := a.policy.Authorize(ctx, authz.AuthorsRead); err != nil {
+		return nil, err
+	}
+	return a.next.GetByFilter(ctx, filter, filterValue, opts)
+}
+
+func (a *authorizedAuthorService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	if err := a.policy.Authorize(ctx, authz.AuthorsRead); err != nil {
+		return nil, err
+	}
+	return a.next.GetByIDs(ctx, ids, opts)
+}
+
+func (a *authorizedAuthorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
+	if err := a.policy.Authorize(ctx, authz.AuthorsUpdate); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, id, author, updatedAuthor, booksPublished)
+}
+
+func (a *authorizedAuthorService) Delete(ctx context.Context, id string, author *domain.Author) error {
+	if err := a.policy.Authorize(ctx, authz.AuthorsDelete); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id, author)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+)
+
+type bookResolvingAuthorService struct {
+	domain.AuthorService
+	books domain.BookRepository
+}
+
+// NewBookResolvingAuthorService wraps a so that the books of a create or an
+// update are looked up in books before a runs, outside the transaction a
+// publishing service opens. books is typically the replica of a remote book
+// service, which refreshes its local copy then and reads only that copy
+// within the transaction.
+func NewBookResolvingAuthorService(a domain.AuthorService, books domain.BookRepository) domain.AuthorService {
+	return &bookResolvingAuthorService{AuthorService: a, books: books}
+}
+
+func (s *bookResolvingAuthorService) Create(ctx context.Context, books []string, author *domain.Author) error {
+	if err := s.resolve(ctx, books); err != nil {
+		return err
+	}
+	return s.AuthorService.Create(ctx, books, author)
+}
+
+func (s *bookResolvingAuthorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
+	if err := s.resolve(ctx, booksPublished); err != nil {
+		return err
+	}
+	return s.AuthorService.Update(ctx, id, author, updatedAuthor, booksPublished)
+}
+
+func (s *bookResolvingAuthorService) resolve(ctx context.Context, isbns []string) error {
+	if len(isbns) == 0 {
+		return nil
+	}
+	_, err := s.books.GetByISBN(ctx, "ISBN", isbns)
+	return err
+}
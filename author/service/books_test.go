@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBookResolvingAuthorService(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	isbns := []string{"978160309028"}
+
+	t.Run("happy path: looks the books up before the write", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorBookRepo := &repository.AuthorBooksRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		resolver := &repository.BookRepositoryMock{}
+		var calls []string
+		resolver.On("GetByISBN", ctx, "ISBN", isbns).Run(func(mock.Arguments) { calls = append(calls, "resolve") }).Return([]domain.Book{{ID: 1}}, nil).Once()
+		bookRepo.On("GetByISBN", ctx, "ISBN", isbns).Run(func(mock.Arguments) { calls = append(calls, "create") }).Return([]domain.Book{{ID: 1}}, nil).Once()
+		authorRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		authorBookRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+		service := NewBookResolvingAuthorService(NewAuthorService(authorRepo, authorBookRepo, bookRepo), resolver)
+
+		as.NoError(service.Create(ctx, isbns, &domain.Author{}))
+		as.Equal([]string{"resolve", "create"}, calls)
+	})
+
+	t.Run("book service down: the write does not start", func(t *testing.T) {
+		resolver := &repository.BookRepositoryMock{}
+		resolver.On("GetByISBN", ctx, "ISBN", isbns).Return([]domain.Book{}, domain.ErrUnavailable).Once()
+		service := NewBookResolvingAuthorService(NewAuthorService(&repository.AuthorRepositoryMock{}, nil, &repository.BookRepositoryMock{}), resolver)
+
+		err := service.Update(ctx, "7", &domain.Author{ID: 7}, domain.Author{Name: "Jane"}, isbns)
+		as.ErrorIs(err, domain.ErrUnavailable)
+	})
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"geniuscrew/domain"
+	"geniuscrew/internal/cache"
+	"geniuscrew/internal/metrics"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+type cachedAuthorService struct {
+	next  domain.AuthorService
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedAuthorService wraps a with a read-through cache of reads at most
+// one association level deep, as NewCachedBookService does. Writes invalidate
+// the author, every cached search and the cached books linked to the author
+// before and after the write, whose representation embeds the author.
+func NewCachedAuthorService(a domain.AuthorService, store cache.Store, ttl time.Duration) domain.AuthorService {
+	return &cachedAuthorService{next: a, store: store, ttl: ttl}
+}
+
+func (s *cachedAuthorService) Create(ctx context.Context, books []string, author *domain.Author) error {
+	err := s.next.Create(ctx, books, author)
+	if author.ID == 0 {
+		return err
+	}
+	if err != nil {
+		// the books may have been linked in part
+		s.invalidate(ctx, strconv.Itoa(author.ID), nil)
+		return err
+	}
+	s.invalidate(ctx, strconv.Itoa(author.ID), author.BooksPublished)
+	return nil
+}
+
+func (s *cachedAuthorService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	if opts.Depth > cache.MaxDepth {
+		return s.next.Get(ctx, id, opts)
+	}
+	var author domain.Author
+	key := cache.Variant(cache.AuthorKey(id), opts.Key())
+	if s.load(ctx, key, &author) {
+		return author, nil
+	}
+	author, err := s.next.Get(ctx, id, opts)
+	if err != nil {
+		return author, err
+	}
+	s.save(ctx, key, author)
+	return author, nil
+}
+
+func (s *cachedAuthorService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	if opts.Depth > cache.MaxDepth {
+		return s.next.GetByFilter(ctx, filter, filterValue, opts)
+	}
+	var authors []domain.Author
+	key := cache.Variant(cache.AuthorSearchKey(filter, filterValue), opts.Key())
+	if s.load(ctx, key, &authors) {
+		return authors, nil
+	}
+	authors, err := s.next.GetByFilter(ctx, filter, filterValue, opts)
+	if err != nil {
+		return authors, err
+	}
+	s.save(ctx, key, authors)
+	return authors, nil
+}
+
+// GetByIDs is not cached: batches rarely repeat, and one entry per batch
+// would multiply what a write has to invalidate.
+func (s *cachedAuthorService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	return s.next.GetByIDs(ctx, ids, opts)
+}
+
+// Update takes the books linked before the write from author, the current
+// author as callers load it, and those linked after from the updated author.
+func (s *cachedAuthorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
+	before := append([]domain.Book{}, author.BooksPublished...)
+	err := s.next.Update(ctx, id, author, updatedAuthor, booksPublished)
+	if err != nil {
+		// some of the new books may have been linked
+		s.invalidate(ctx, id, nil)
+		return err
+	}
+	s.invalidate(ctx, id, append(before, author.BooksPublished...))
+	return nil
+}
+
+func (s *cachedAuthorService) Delete(ctx context.Context, id string, author *domain.Author) error {
+	before := s.linkedBooks(ctx, id)
+	err := s.next.Delete(ctx, id, author)
+	s.invalidate(ctx, id, before)
+	return err
+}
+
+// linkedBooks returns the books currently linked to author id, read past the
+// cache. A nil result means the lookup failed.
+func (s *cachedAuthorService) linkedBooks(ctx context.Context, id string) []domain.Book {
+	author, err := s.next.Get(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil
+	}
+	if author.BooksPublished == nil {
+		return []domain.Book{}
+	}
+	return author.BooksPublished
+}
+
+// invalidate drops everything that may embed author id. When the linked books
+// are unknown every cached book is dropped. It also runs after a failed
+// write, which may have been applied partially.
+func (s *cachedAuthorService) invalidate(ctx context.Context, id string, books []domain.Book) {
+	keys := []string{cache.AuthorKey(id)}
+	if books == nil {
+		s.store.DeletePrefix(ctx, cache.BookKey(""))
+	}
+	for _, book := range books {
+		keys = append(keys, cache.BookKey(strconv.Itoa(book.ID)))
+	}
+	cache.DeleteVariants(ctx, s.store, keys...)
+	s.store.DeletePrefix(ctx, cache.AuthorSearchPrefix)
+	s.store.DeletePrefix(ctx, cache.BookSearchPrefix)
+}
+
+func (s *cachedAuthorService) load(ctx context.Context, key string, v interface{}) bool {
+	data, ok := s.store.Get(ctx, key)
+	if ok && json.Unmarshal(data, v) == nil {
+		metrics.CacheHits.WithLabelValues("authors").Inc()
+		return true
+	}
+	metrics.CacheMisses.WithLabelValues("authors").Inc()
+	return false
+}
+
+func (s *cachedAuthorService) save(ctx context.Context, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.WarnContext(ctx, "caching author failed", "layer", "service", "key", key, "error", err)
+		return
+	}
+	s.store.Set(ctx, key, data, s.ttl)
+}
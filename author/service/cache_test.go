@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCachedAuthorService(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	id := "7"
+	author := domain.Author{ID: 7, Name: "John", BooksPublished: []domain.Book{{ID: 1, ISBN: "978160309028"}}}
+	newService := func(authorRepo *repository.AuthorRepositoryMock, authorBookRepo *repository.AuthorBooksRepositoryMock, bookRepo *repository.BookRepositoryMock, store cache.Store) domain.AuthorService {
+		return NewCachedAuthorService(NewAuthorService(authorRepo, authorBookRepo, bookRepo), store, time.Minute)
+	}
+
+	t.Run("happy path: second read is served from the cache", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(author, nil).Once()
+		service := newService(authorRepo, nil, nil, cache.NewLRU(10, 0))
+		first, err := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(err)
+		second, err := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(err)
+		as.Equal(first.Name, second.Name)
+		as.Equal("978160309028", second.BooksPublished[0].ISBN)
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("happy path: variants are cached apart", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		sparse := domain.QueryOptions{Fields: []string{"name"}}
+		authorRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(author, nil).Once()
+		authorRepo.On("Get", ctx, id, sparse).Return(domain.Author{ID: 7, Name: "John"}, nil).Once()
+		service := newService(authorRepo, nil, nil, cache.NewLRU(10, 0))
+		for i := 0; i < 2; i++ {
+			full, err := service.Get(ctx, id, domain.QueryOptions{})
+			as.NoError(err)
+			as.Len(full.BooksPublished, 1)
+			narrow, err := service.Get(ctx, id, sparse)
+			as.NoError(err)
+			as.Empty(narrow.BooksPublished)
+		}
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("create invalidates the books it links without reading the author", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorBookRepo := &repository.AuthorBooksRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		store := cache.NewLRU(10, 0)
+		store.Set(ctx, cache.BookKey("1"), []byte(`{}`), time.Minute)
+		store.Set(ctx, cache.BookKey("3"), []byte(`{}`), time.Minute)
+		books := []domain.Book{{ID: 1, ISBN: "978160309028"}}
+		bookRepo.On("GetByISBN", ctx, "ISBN", []string{"978160309028"}).Return(books, nil).Once()
+		authorRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Author).ID = 7
+		}).Return(nil).Once()
+		authorBookRepo.On("Create", ctx, mock.Anything, books).Return(nil).Once()
+		service := newService(authorRepo, authorBookRepo, bookRepo, store)
+
+		created := domain.Author{Name: "John"}
+		as.NoError(service.Create(ctx, []string{"978160309028"}, &created))
+		as.Equal(books, created.BooksPublished)
+		_, ok := store.Get(ctx, cache.BookKey("1"))
+		as.False(ok)
+		_, ok = store.Get(ctx, cache.BookKey("3"))
+		as.True(ok)
+		// no Get was expected
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("update invalidates the author, its variants, searches and the books linked before and after", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorBookRepo := &repository.AuthorBooksRepositoryMock{}
+		bookRepo := &repository.BookRepositoryMock{}
+		store := cache.NewLRU(10, 0)
+		for _, key := range []string{cache.BookKey("1"), cache.BookKey("2"), cache.BookKey("3"), cache.Variant(cache.AuthorKey(id), "fields=name"), cache.AuthorSearchKey("name", "John")} {
+			store.Set(ctx, key, []byte(`{}`), time.Minute)
+		}
+		authorRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(author, nil).Once()
+		bookRepo.On("GetByISBN", ctx, "ISBN", []string{"978316148410"}).Return([]domain.Book{{ID: 2, ISBN: "978316148410"}}, nil).Once()
+		authorRepo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+		authorBookRepo.On("Update", ctx, id, mock.Anything, mock.Anything).Return(nil).Once()
+		service := newService(authorRepo, authorBookRepo, bookRepo, store)
+
+		current, _ := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(service.Update(ctx, id, &current, domain.Author{Name: "Jane"}, []string{"978316148410"}))
+		for _, key := range []string{cache.BookKey("1"), cache.BookKey("2"), cache.Variant(cache.AuthorKey(id), "fields=name"), cache.AuthorKey(id), cache.AuthorSearchKey("name", "John")} {
+			_, ok := store.Get(ctx, key)
+			as.False(ok, key)
+		}
+		_, ok := store.Get(ctx, cache.BookKey("3"))
+		as.True(ok)
+		// the update reads nothing past the cache
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed update invalidates every book", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		store := cache.NewLRU(10, 0)
+		store.Set(ctx, cache.BookKey("3"), []byte(`{}`), time.Minute)
+		authorRepo.On("Update", ctx, mock.Anything, mock.Anything).Return(errors.New("failed")).Once()
+		service := newService(authorRepo, &repository.AuthorBooksRepositoryMock{}, &repository.BookRepositoryMock{}, store)
+		current := author
+		as.Error(service.Update(ctx, id, &current, domain.Author{Name: "Jane"}, nil))
+		_, ok := store.Get(ctx, cache.BookKey("3"))
+		as.False(ok)
+	})
+
+	t.Run("delete invalidates the author and its linked books", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorBookRepo := &repository.AuthorBooksRepositoryMock{}
+		store := cache.NewLRU(10, 0)
+		store.Set(ctx, cache.AuthorKey(id), []byte(`{}`), time.Minute)
+		store.Set(ctx, cache.BookKey("1"), []byte(`{}`), time.Minute)
+		store.Set(ctx, cache.BookKey("3"), []byte(`{}`), time.Minute)
+		authorRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(author, nil).Once()
+		authorBookRepo.On("Delete", ctx, id).Return(nil).Once()
+		authorRepo.On("Delete", ctx, id, mock.Anything).Return(nil).Once()
+		service := newService(authorRepo, authorBookRepo, nil, store)
+
+		as.NoError(service.Delete(ctx, id, &domain.Author{}))
+		_, ok := store.Get(ctx, cache.AuthorKey(id))
+		as.False(ok)
+		_, ok = store.Get(ctx, cache.BookKey("1"))
+		as.False(ok)
+		_, ok = store.Get(ctx, cache.BookKey("3"))
+		as.True(ok)
+		authorRepo.AssertExpectations(t)
+	})
+}
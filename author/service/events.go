@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"strconv"
+)
+
+type publishingAuthorService struct {
+	next       domain.AuthorService
+	transactor domain.Transactor
+	publisher  domain.EventPublisher
+	mapper     v1.Mapper
+}
+
+// NewPublishingAuthorService wraps a so that every write publishes an
+// author.created, author.updated or author.deleted event, and an
+// author.books_changed event when an update changes the linked books, in the
+// same transaction as the write. The author is read back in that transaction
+// after creates and updates so that events carry the linked books.
+func NewPublishingAuthorService(a domain.AuthorService, transactor domain.Transactor, publisher domain.EventPublisher, mapper v1.Mapper) domain.AuthorService {
+	return &publishingAuthorService{next: a, transactor: transactor, publisher: publisher, mapper: mapper}
+}
+
+func (s *publishingAuthorService) Create(ctx context.Context, books []string, author *domain.Author) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Create(ctx, books, author)
+		if err != nil {
+			return err
+		}
+		created, err := s.next.Get(ctx, strconv.Itoa(author.ID), domain.QueryOptions{})
+		if err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventAuthorCreated, authorAggregate(created.ID), s.mapper.Author(created)))
+	})
+}
+
+func (s *publishingAuthorService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	return s.next.Get(ctx, id, opts)
+}
+
+func (s *publishingAuthorService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	return s.next.GetByFilter(ctx, filter, filterValue, opts)
+}
+
+func (s *publishingAuthorService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	return s.next.GetByIDs(ctx, ids, opts)
+}
+
+// Update compares the ISBNs linked before, as loaded into author by the
+// caller, with those linked after.
+func (s *publishingAuthorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
+	before := isbns(author.BooksPublished)
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Update(ctx, id, author, updatedAuthor, booksPublished)
+		if err != nil {
+			return err
+		}
+		updated, err := s.next.Get(ctx, id, domain.QueryOptions{})
+		if err != nil {
+			return err
+		}
+		if updatedAuthor.Name != "" || updatedAuthor.Surname != "" || updatedAuthor.Email != "" {
+			err = s.publisher.Publish(ctx, domain.NewEvent(domain.EventAuthorUpdated, authorAggregate(updated.ID), s.mapper.Author(updated)))
+			if err != nil {
+				return err
+			}
+		}
+		after := isbns(updated.BooksPublished)
+		added, removed := difference(after, before), difference(before, after)
+		if len(added) == 0 && len(removed) == 0 {
+			return nil
+		}
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventAuthorBooksChanged, authorAggregate(updated.ID), v1.AuthorBooksChanged{
+			ID:             updated.ID,
+			BooksPublished: after,
+			Added:          added,
+			Removed:        removed,
+		}))
+	})
+}
+
+func (s *publishingAuthorService) Delete(ctx context.Context, id string, author *domain.Author) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Delete(ctx, id, author)
+		if err != nil {
+			return err
+		}
+		authorID, _ := strconv.Atoi(id)
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventAuthorDeleted, authorAggregate(authorID), v1.Deleted{ID: authorID}))
+	})
+}
+
+func authorAggregate(id int) string {
+	return "author/" + strconv.Itoa(id)
+}
+
+func isbns(books []domain.Book) []string {
+	out := make([]string, 0, len(books))
+	for _, book := range books {
+		out = append(out, book.ISBN)
+	}
+	return out
+}
+
+// difference returns the entries of a missing from b.
+func difference(a, b []string) []string {
+	out := []string{}
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
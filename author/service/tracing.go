@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedAuthorService struct {
+	next domain.AuthorService
+}
+
+// NewTracedAuthorService wraps a so that every method runs in its own span.
+func NewTracedAuthorService(a domain.AuthorService) domain.AuthorService {
+	return &tracedAuthorService{next: a}
+}
+
+func (t *tracedAuthorService) Create(ctx context.Context, books []string, author *domain.Author) error {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.Create", trace.WithAttributes(attribute.Int("books", len(books))))
+	defer span.End()
+	err := t.next.Create(ctx, books, author)
+	return tracing.End(span, err)
+}
+
+func (t *tracedAuthorService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.Get", trace.WithAttributes(attribute.String("author.id", id), attribute.String("query", opts.Key())))
+	defer span.End()
+	author, err := t.next.Get(ctx, id, opts)
+	return author, tracing.End(span, err)
+}
+
+func (t *tracedAuthorService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.GetByFilter", trace.WithAttributes(attribute.String("filter", filter), attribute.String("query", opts.Key())))
+	defer span.End()
+	authors, err := t.next.GetByFilter(ctx, filter, filterValue, opts)
+	span.SetAttributes(attribute.Int("results", len(authors)))
+	return authors, tracing.End(span, err)
+}
+
+func (t *tracedAuthorService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.GetByIDs", trace.WithAttributes(attribute.Int("ids", len(ids)), attribute.String("query", opts.Key())))
+	defer span.End()
+	authors, err := t.next.GetByIDs(ctx, ids, opts)
+	span.SetAttributes(attribute.Int("results", len(authors)))
+	return authors, tracing.End(span, err)
+}
+
+func (t *tracedAuthorService) Update(ctx context.Context, id string, author *domain.Author, updatedAuthor domain.Author, booksPublished []string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.Update", trace.WithAttributes(attribute.String("author.id", id), attribute.Int("books", len(booksPublished))))
+	defer span.End()
+	err := t.next.Update(ctx, id, author, updatedAuthor, booksPublished)
+	return tracing.End(span, err)
+}
+
+func (t *tracedAuthorService) Delete(ctx context.Context, id string, author *domain.Author) error {
+	ctx, span := tracing.Tracer().Start(ctx, "AuthorService.Delete", trace.WithAttributes(attribute.String("author.id", id)))
+	defer span.End()
+	err := t.next.Delete(ctx, id, author)
+	return tracing.End(span, err)
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	catalogv1 "geniuscrew/api/proto/catalog/v1"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/rpc"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// bookFields maps the searchable fields to the filter of BookService.
+var bookFields = map[catalogv1.BookField]string{
+	catalogv1.BookField_BOOK_FIELD_TITLE:       "title",
+	catalogv1.BookField_BOOK_FIELD_DESCRIPTION: "description",
+}
+
+type BookServer struct {
+	catalogv1.UnimplementedBookServiceServer
+	BookService domain.BookService
+	Mapper      v1.Mapper
+}
+
+func NewBookServer(server *grpc.Server, bs domain.BookService, mapper v1.Mapper) {
+	catalogv1.RegisterBookServiceServer(server, &BookServer{
+		BookService: bs,
+		Mapper:      mapper,
+	})
+}
+
+func (p *BookServer) CreateBook(ctx context.Context, req *catalogv1.CreateBookRequest) (*catalogv1.Book, error) {
+	input := v1.CreateBookRequest{
+		Title:             req.GetTitle(),
+		Description:       req.GetDescription(),
+		ISBN:              req.GetIsbn(),
+		PublishingCompany: req.GetPublishingCompany(),
+	}
+	if inputErr := appvalidator.InputValidator(input); inputErr != nil {
+		return nil, rpc.InvalidArgument(inputErr)
+	}
+	book := input.Book()
+	if err := p.BookService.Create(ctx, &book); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromBook(p.Mapper.Book(book)), nil
+}
+
+func (p *BookServer) GetBook(ctx context.Context, req *catalogv1.GetBookRequest) (*catalogv1.Book, error) {
+	id, err := bookID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	book, err := p.BookService.Get(ctx, id, domain.QueryOptions{Depth: p.Mapper.Depth})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromBook(p.Mapper.Book(book)), nil
+}
+
+func (p *BookServer) BatchGetBooks(ctx context.Context, req *catalogv1.BatchGetBooksRequest) (*catalogv1.BatchGetBooksResponse, error) {
+	ids, err := rpc.IDs(req.GetIds())
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &catalogv1.BatchGetBooksResponse{}, nil
+	}
+	books, err := p.BookService.GetByIDs(ctx, ids, domain.QueryOptions{Depth: p.Mapper.Depth})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &catalogv1.BatchGetBooksResponse{Books: catalogv1.FromBooks(p.Mapper.Books(books))}, nil
+}
+
+func (p *BookServer) SearchBooks(ctx context.Context, req *catalogv1.SearchBooksRequest) (*catalogv1.SearchBooksResponse, error) {
+	filter, ok := bookFields[req.GetField()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "field must be one of BOOK_FIELD_TITLE, BOOK_FIELD_DESCRIPTION")
+	}
+	limit, offset, err := rpc.Page(req.GetLimit(), req.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+	books, err := p.BookService.GetByFilter(ctx, filter, req.GetValue(), domain.QueryOptions{Depth: p.Mapper.Depth, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &catalogv1.SearchBooksResponse{Books: catalogv1.FromBooks(p.Mapper.Books(books))}, nil
+}
+
+func (p *BookServer) UpdateBook(ctx context.Context, req *catalogv1.UpdateBookRequest) (*catalogv1.Book, error) {
+	id, err := bookID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	input := v1.UpdateBookRequest{
+		Title:             req.GetTitle(),
+		Description:       req.GetDescription(),
+		ISBN:              req.GetIsbn(),
+		PublishingCompany: req.GetPublishingCompany(),
+	}
+	if inputErr := appvalidator.InputValidator(input); inputErr != nil {
+		return nil, rpc.InvalidArgument(inputErr)
+	}
+	book, err := p.BookService.Get(ctx, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	if err := p.BookService.Update(ctx, id, &book, input.Book()); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return catalogv1.FromBook(p.Mapper.Book(book)), nil
+}
+
+func (p *BookServer) DeleteBook(ctx context.Context, req *catalogv1.DeleteBookRequest) (*emptypb.Empty, error) {
+	id, err := bookID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	var book domain.Book
+	if err := p.BookService.Delete(ctx, id, &book); err != nil {
+		return nil, rpc.Error(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// bookID returns id in the form the service takes it.
+func bookID(id int64) (string, error) {
+	if id < 1 {
+		return "", status.Error(codes.InvalidArgument, "invalid id parameter")
+	}
+	return strconv.FormatInt(id, 10), nil
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	catalogv1 "geniuscrew/api/proto/catalog/v1"
+	v1 "geniuscrew/api/v1"
+	_bookService "geniuscrew/book/service"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/ratelimit"
+	"geniuscrew/internal/rpc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves the book service over an in-memory listener the way
+// cmd/api does and returns a client connection to it.
+func dial(t *testing.T, bookRepo *repository.BookRepositoryMock) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	server, _ := rpc.NewServer(auth.NewAuthenticator(nil, &repository.APIKeyRepositoryMock{}), ratelimit.NewMemoryStore(), nil, time.Second)
+	NewBookServer(server, _bookService.NewBookService(bookRepo), v1.Mapper{Depth: 1})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGetBook(t *testing.T) {
+	as := assert.New(t)
+	bookRepo := &repository.BookRepositoryMock{}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	bookRepo.On("Get", mock.Anything, "1", domain.QueryOptions{Depth: 1}).Return(domain.Book{
+		ID:        1,
+		Title:     "Go",
+		ISBN:      "978-0134190440",
+		Authors:   []domain.Author{{ID: 7, Name: "Alan", Email: "alan@example.com"}},
+		CreatedAt: created,
+	}, nil).Once()
+	bookRepo.On("Get", mock.Anything, "2", domain.QueryOptions{Depth: 1}).Return(domain.Book{}, domain.ErrRecordNotFound).Once()
+	client := catalogv1.NewBookServiceClient(dial(t, bookRepo))
+
+	book, err := client.GetBook(context.Background(), &catalogv1.GetBookRequest{Id: 1})
+	as.NoError(err)
+	as.Equal("Go", book.GetTitle())
+	as.Equal("978-0134190440", book.GetIsbn())
+	as.Equal(created, book.GetCreatedAt().AsTime())
+	as.Len(book.GetAuthors(), 1)
+	as.Equal("Alan", book.GetAuthors()[0].GetName())
+
+	_, err = client.GetBook(context.Background(), &catalogv1.GetBookRequest{Id: 2})
+	as.Equal(codes.NotFound, status.Code(err))
+
+	_, err = client.GetBook(context.Background(), &catalogv1.GetBookRequest{})
+	as.Equal(codes.InvalidArgument, status.Code(err))
+	bookRepo.AssertExpectations(t)
+}
+
+func TestBookWritesRequireCredentials(t *testing.T) {
+	as := assert.New(t)
+	client := catalogv1.NewBookServiceClient(dial(t, &repository.BookRepositoryMock{}))
+
+	_, err := client.CreateBook(context.Background(), &catalogv1.CreateBookRequest{Title: "Go"})
+	as.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = client.SearchBooks(context.Background(), &catalogv1.SearchBooksRequest{Value: "go"})
+	as.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestInvalidArgumentCarriesFieldViolations(t *testing.T) {
+	as := assert.New(t)
+	st := status.Convert(rpc.InvalidArgument(map[string]string{
+		"Title": "Title failed validation",
+		"ISBN":  "ISBN failed validation",
+	}))
+	as.Equal(codes.InvalidArgument, st.Code())
+	as.Len(st.Details(), 1)
+	details := st.Details()[0].(*errdetails.BadRequest)
+	as.Equal("ISBN", details.GetFieldViolations()[0].GetField())
+	as.Equal("Title", details.GetFieldViolations()[1].GetField())
+}
+
+func TestHealth(t *testing.T) {
+	as := assert.New(t)
+	client := healthpb.NewHealthClient(dial(t, &repository.BookRepositoryMock{}))
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	as.NoError(err)
+	as.Equal(healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+}
@@ -1,26 +1,33 @@
 package http
 
 import (
-	"context"
 	"errors"
+	v1 "geniuscrew/api/v1"
 	"geniuscrew/domain"
 	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/conditional"
 	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/render"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type BookHandler struct {
 	BookService domain.BookService
+	Mapper      v1.Mapper
 }
 
-func NewBookHandler(router *gin.Engine, p domain.BookService) {
+func NewBookHandler(router *gin.Engine, p domain.BookService, mapper v1.Mapper) {
 	handler := &BookHandler{
 		BookService: p,
+		Mapper:      mapper,
 	}
 	api := router.Group("/api/v1")
 	api.POST("/books", handler.CreateBook)
+	api.GET("/books", handler.ListBooks)
 	api.GET("/books/:id", handler.GetBookByID)
 	api.GET("/books/filter", handler.GetByFilter)
 	api.PUT("/books/:id", handler.UpdateBookByID)
@@ -28,130 +35,137 @@ func NewBookHandler(router *gin.Engine, p domain.BookService) {
 }
 
 func (p *BookHandler) CreateBook(c *gin.Context) {
-	var input struct {
-		Title             string `json:"title" validate:"gte=0,lte=500,required"`
-		Description       string `json:"description" validate:"gte=0,lte=500,required"`
-		ISBN              string `json:"ISBN" validate:"gte=0,lte=14,required"`
-		PublishingCompany string `json:"publishing_company" validate:"gte=0,lte=50,required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input v1.CreateBookRequest
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	inputErr := appvalidator.InputValidator(input)
 	if inputErr != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
 		return
 	}
 
-	var ctx = context.TODO()
-	var book domain.Book
-	book.Title = input.Title
-	book.Description = input.Description
-	book.ISBN = input.ISBN
-	book.PublishingCompany = input.PublishingCompany
+	var ctx = c.Request.Context()
+	book := input.Book()
 
 	err := p.BookService.Create(ctx, &book)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrDuplicateRecord):
-			c.JSON(http.StatusConflict, gin.H{"error": "book exists"})
+			render.Respond(c, http.StatusConflict, gin.H{"error": "book exists"})
 			return
 		case errors.Is(err, domain.ErrBookNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"payload": book})
+	render.Respond(c, http.StatusOK, gin.H{"payload": p.Mapper.Book(book)})
 }
 
 func (p *BookHandler) GetBookByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var ctx = context.TODO()
-	book, err := p.BookService.Get(ctx, id)
+	opts, err := domain.ParseQueryOptions(c.Query("fields"), c.Query("include"), domain.BookColumns, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Depth = p.Mapper.Depth
+	var ctx = c.Request.Context()
+	book, err := p.BookService.Get(ctx, id, opts)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrRecordNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusFound, gin.H{"payload": book})
+	payload, err := helpers.Sparse(p.Mapper.Book(book), opts, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := gin.H{"payload": payload}
+	format := render.Format(c, response)
+	if format == "" {
+		render.NotAcceptable(c, response)
+		return
+	}
+	etag, err := conditional.ETag(format, payload)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if conditional.NotModified(c, etag, book.LastModified()) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	render.Respond(c, http.StatusFound, response)
 }
 
 func (p *BookHandler) UpdateBookByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var input struct {
-		Title             string `json:"title" validate:"isdefault|gte=0,lte=500"`
-		Description       string `json:"description" validate:"isdefault|gte=0,lte=500"`
-		ISBN              string `json:"ISBN" validate:"isdefault|gte=0,lte=14"`
-		PublishingCompany string `json:"publishing_company" validate:"isdefault|gte=0, lte=50"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input v1.UpdateBookRequest
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	inputErr := appvalidator.InputValidator(input)
 	if inputErr != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
 		return
 	}
-	var ctx = context.TODO()
-	book, err := p.BookService.Get(ctx, id)
+	var ctx = c.Request.Context()
+	book, err := p.BookService.Get(ctx, id, domain.QueryOptions{})
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrRecordNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	var updatedBook domain.Book
-	updatedBook.Title = input.Title
-	updatedBook.Description = input.Description
-	updatedBook.ISBN = input.ISBN
-	updatedBook.PublishingCompany = input.PublishingCompany
-	err = p.BookService.Update(ctx, id, &book, updatedBook)
+	err = p.BookService.Update(ctx, id, &book, input.Book())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, book)
+	render.Respond(c, http.StatusOK, p.Mapper.Book(book))
 }
 
 func (p *BookHandler) DeleteBookByID(c *gin.Context) {
 	id := c.Param("id")
 	err := appvalidator.IsIDValid(id)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
-	var ctx = context.TODO()
+	var ctx = c.Request.Context()
 	var book domain.Book
 	err = p.BookService.Delete(ctx, id, &book)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Book deleted successfully"})
+	render.Respond(c, http.StatusOK, gin.H{"message": "Book deleted successfully"})
 }
 
 func (p *BookHandler) GetByFilter(c *gin.Context) {
@@ -161,21 +175,109 @@ func (p *BookHandler) GetByFilter(c *gin.Context) {
 	if !helpers.In(filter, filterSafeList...) {
 		message := make(map[string][]string)
 		message["filter_fields_allowed"] = filterSafeList
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": message})
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": message})
 		return
 	}
-	var ctx = context.TODO()
+	opts, err := domain.ParseQueryOptions(c.Query("fields"), c.Query("include"), domain.BookColumns, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Limit, opts.Offset, err = domain.ParsePage(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Depth = p.Mapper.Depth
+	var ctx = c.Request.Context()
 
-	book, err := p.BookService.GetByFilter(ctx, filter, filterValue)
+	book, err := p.BookService.GetByFilter(ctx, filter, filterValue, opts)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrBookNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
 			return
 		}
 	}
-	c.JSON(http.StatusFound, gin.H{"payload": book})
+	payload, err := helpers.Sparse(p.Mapper.Books(book), opts, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	render.Respond(c, http.StatusFound, gin.H{"payload": payload})
+}
+
+// maxLookup bounds the number of ids or ISBNs ListBooks looks up at once.
+const maxLookup = 100
+
+// ListBooks looks books up by a comma separated list of ids or of ISBNs.
+// Values that match no book are left out of the result.
+func (p *BookHandler) ListBooks(c *gin.Context) {
+	ids, isbns := split(c.Query("id")), split(c.Query("isbn"))
+	if (len(ids) == 0) == (len(isbns) == 0) {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "exactly one of id and isbn is required"})
+		return
+	}
+	if len(ids)+len(isbns) > maxLookup {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "at most " + strconv.Itoa(maxLookup) + " values can be looked up at once"})
+		return
+	}
+	opts, err := domain.ParseQueryOptions(c.Query("fields"), c.Query("include"), domain.BookColumns, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	opts.Depth = p.Mapper.Depth
+	var ctx = c.Request.Context()
+
+	var books []domain.Book
+	if len(isbns) > 0 {
+		books, err = p.BookService.GetByISBNs(ctx, isbns)
+		// the ISBN lookup reads columns only; authors come from a second read
+		if err == nil && len(books) > 0 && opts.Includes("authors") {
+			books, err = p.BookService.GetByIDs(ctx, bookIDs(books), opts)
+		}
+	} else {
+		var bookIDs []int
+		for _, id := range ids {
+			if appvalidator.IsIDValid(id) != nil {
+				render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "invalid id parameter"})
+				return
+			}
+			n, _ := strconv.Atoi(id)
+			bookIDs = append(bookIDs, n)
+		}
+		books, err = p.BookService.GetByIDs(ctx, bookIDs, opts)
+	}
+	if err != nil {
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+		return
+	}
+	payload, err := helpers.Sparse(p.Mapper.Books(books), opts, domain.BookAssociations)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	render.Respond(c, http.StatusFound, gin.H{"payload": payload})
+}
+
+func split(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func bookIDs(books []domain.Book) []int {
+	ids := make([]int, 0, len(books))
+	for _, book := range books {
+		ids = append(ids, book.ID)
+	}
+	return ids
 }
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/logger"
+	"geniuscrew/internal/tracing"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxLookup is the number of ids or ISBNs the book service looks up at once.
+const maxLookup = 100
+
+// lookupFields selects the columns of a book without its authors.
+const lookupFields = "id,title,description,ISBN,publication_date,publishing_company,created_at,updated_at"
+
+// Config locates the book service and bounds the calls made to it.
+type Config struct {
+	// BaseURL is the root of the book service, e.g. http://books:8080.
+	BaseURL string
+	// APIKey, when set, authenticates the calls.
+	APIKey string
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Retries is the number of further attempts made for reads, updates
+	// and deletes that fail because the service is unreachable or
+	// overloaded. Creates are never retried.
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each one after.
+	Backoff time.Duration
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+type httpBookRepository struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewHTTPBookRepository returns a BookRepository backed by the REST API of a
+// book service, so that a service deployed apart from the books database can
+// still read and write books. When the book service cannot be reached the
+// methods return an error wrapping domain.ErrUnavailable.
+func NewHTTPBookRepository(cfg Config) domain.BookRepository {
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &httpBookRepository{cfg: cfg, client: client}
+}
+
+func (m *httpBookRepository) Create(ctx context.Context, book *domain.Book) error {
+	body := v1.CreateBookRequest{
+		Title:             book.Title,
+		Description:       book.Description,
+		ISBN:              book.ISBN,
+		PublishingCompany: book.PublishingCompany,
+	}
+	var out struct {
+		Payload v1.Book `json:"payload"`
+	}
+	if err := m.do(ctx, http.MethodPost, "/api/v1/books", nil, body, &out); err != nil {
+		return err
+	}
+	*book = fromV1(out.Payload)
+	return nil
+}
+
+func (m *httpBookRepository) Update(ctx context.Context, book *domain.Book, updatedBook domain.Book) error {
+	body := v1.UpdateBookRequest{
+		Title:             updatedBook.Title,
+		Description:       updatedBook.Description,
+		ISBN:              updatedBook.ISBN,
+		PublishingCompany: updatedBook.PublishingCompany,
+	}
+	var out v1.Book
+	if err := m.do(ctx, http.MethodPut, "/api/v1/books/"+strconv.Itoa(book.ID), nil, body, &out); err != nil {
+		return err
+	}
+	*book = fromV1(out)
+	return nil
+}
+
+func (m *httpBookRepository) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	var out struct {
+		Payload v1.Book `json:"payload"`
+	}
+	if err := m.do(ctx, http.MethodGet, "/api/v1/books/"+url.PathEscape(id), queryOf(opts), nil, &out); err != nil {
+		return domain.Book{}, err
+	}
+	return fromV1(out.Payload), nil
+}
+
+func (m *httpBookRepository) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	query := queryOf(opts)
+	query.Set("field", filter)
+	query.Set("value", filterValue)
+	var out struct {
+		Payload []v1.Book `json:"payload"`
+	}
+	err := m.do(ctx, http.MethodGet, "/api/v1/books/filter", query, nil, &out)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		// the book service answers 404 when nothing matches
+		return []domain.Book{}, nil
+	}
+	if err != nil {
+		return []domain.Book{}, err
+	}
+	return fromV1List(out.Payload), nil
+}
+
+func (m *httpBookRepository) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return m.lookup(ctx, "id", values, queryOf(opts))
+}
+
+func (m *httpBookRepository) Delete(ctx context.Context, id string, book *domain.Book) error {
+	return m.do(ctx, http.MethodDelete, "/api/v1/books/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetByISBN reads the books with the given ISBNs without their authors.
+// Only the ISBN field can be looked up remotely.
+func (m *httpBookRepository) GetByISBN(ctx context.Context, field string, filter []string) ([]domain.Book, error) {
+	if field != "ISBN" {
+		return []domain.Book{}, fmt.Errorf("book service: cannot look books up by %q", field)
+	}
+	return m.lookup(ctx, "isbn", filter, url.Values{"fields": {lookupFields}})
+}
+
+// lookup reads the books matching values of key in batches the book service
+// accepts.
+func (m *httpBookRepository) lookup(ctx context.Context, key string, values []string, query url.Values) ([]domain.Book, error) {
+	books := []domain.Book{}
+	for start := 0; start < len(values); start += maxLookup {
+		end := min(start+maxLookup, len(values))
+		query.Set(key, strings.Join(values[start:end], ","))
+		var out struct {
+			Payload []v1.Book `json:"payload"`
+		}
+		if err := m.do(ctx, http.MethodGet, "/api/v1/books", query, nil, &out); err != nil {
+			return []domain.Book{}, err
+		}
+		books = append(books, fromV1List(out.Payload)...)
+	}
+	return books, nil
+}
+
+// do sends one call to the book service, retrying idempotent ones, and
+// decodes the JSON response into out.
+func (m *httpBookRepository) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	target := m.cfg.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "BookService "+method+" "+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.request.method", method)),
+	)
+	defer span.End()
+
+	attempts := 1
+	if method != http.MethodPost {
+		attempts += max(m.cfg.Retries, 0)
+	}
+	backoff := m.cfg.Backoff
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			slog.WarnContext(ctx, "book service unavailable, retrying", "layer", "repository", "attempt", attempt-1, "retry_in", backoff.String(), "error", lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		status, data, err := m.send(ctx, method, target, payload)
+		if err != nil {
+			if ctx.Err() != nil {
+				// the caller gave up; its deadline is not the book service's fault
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if retryable(status) {
+			lastErr = fmt.Errorf("status %d: %s", status, message(data))
+			continue
+		}
+		if err := statusError(status, data); err != nil {
+			return err
+		}
+		if out == nil {
+			return nil
+		}
+		return json.Unmarshal(data, out)
+	}
+	span.SetStatus(codes.Error, lastErr.Error())
+	return fmt.Errorf("%w: book service at %s: %v", domain.ErrUnavailable, m.cfg.BaseURL, lastErr)
+}
+
+// send makes one attempt, bounded by the configured timeout, and returns
+// the status and body of the response.
+func (m *httpBookRepository) send(ctx context.Context, method, target string, payload []byte) (int, []byte, error) {
+	if m.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if m.cfg.APIKey != "" {
+		req.Header.Set(auth.APIKeyHeader, m.cfg.APIKey)
+	}
+	if id := logger.RequestID(ctx); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	res, err := m.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return res.StatusCode, data, nil
+}
+
+// retryable reports whether a response status means the book service could
+// not handle the call right now.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// statusError maps a response status to the error a repository would
+// return. Read endpoints answer 302, so it counts as success.
+func statusError(status int, data []byte) error {
+	switch {
+	case status < http.StatusMultipleChoices, status == http.StatusFound:
+		return nil
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", domain.ErrRecordNotFound, message(data))
+	case status == http.StatusConflict:
+		return domain.ErrDuplicateRecord
+	case status == http.StatusUnauthorized:
+		return fmt.Errorf("%w: book service: %s", domain.ErrUnauthorized, message(data))
+	case status == http.StatusForbidden:
+		return fmt.Errorf("%w: book service: %s", domain.ErrForbidden, message(data))
+	}
+	return fmt.Errorf("book service answered %d: %s", status, message(data))
+}
+
+// message extracts the error of a book service response body.
+func message(data []byte) string {
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &body) != nil || body.Error == nil {
+		return strings.TrimSpace(string(data))
+	}
+	var s string
+	if json.Unmarshal(body.Error, &s) == nil {
+		return s
+	}
+	return string(body.Error)
+}
+
+func queryOf(opts domain.QueryOptions) url.Values {
+	query := url.Values{}
+	if len(opts.Fields) > 0 {
+		query.Set("fields", strings.Join(opts.Fields, ","))
+	}
+	if len(opts.Include) > 0 {
+		query.Set("include", strings.Join(opts.Include, ","))
+	}
+	return query
+}
+
+func fromV1(b v1.Book) domain.Book {
+	book := domain.Book{
+		ID:                b.ID,
+		Title:             b.Title,
+		Description:       b.Description,
+		ISBN:              b.ISBN,
+		PublicationDate:   b.PublicationDate,
+		PublishingCompany: b.PublishingCompany,
+		CreatedAt:         b.CreatedAt,
+		UpdatedAt:         b.UpdatedAt,
+	}
+	for _, a := range b.Authors {
+		book.Authors = append(book.Authors, authorFromSummary(a))
+	}
+	return book
+}
+
+func fromV1List(books []v1.Book) []domain.Book {
+	out := make([]domain.Book, 0, len(books))
+	for _, b := range books {
+		out = append(out, fromV1(b))
+	}
+	return out
+}
+
+func authorFromSummary(a v1.AuthorSummary) domain.Author {
+	author := domain.Author{ID: a.ID, Name: a.Name, Surname: a.Surname}
+	for _, b := range a.BooksPublished {
+		author.BooksPublished = append(author.BooksPublished, bookFromSummary(b))
+	}
+	return author
+}
+
+func bookFromSummary(b v1.BookSummary) domain.Book {
+	book := domain.Book{ID: b.ID, Title: b.Title, ISBN: b.ISBN}
+	for _, a := range b.Authors {
+		book.Authors = append(book.Authors, authorFromSummary(a))
+	}
+	return book
+}
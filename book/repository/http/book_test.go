@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"geniuscrew/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRepository(url string) domain.BookRepository {
+	return NewHTTPBookRepository(Config{
+		BaseURL: url,
+		APIKey:  "gck_test_secret",
+		Timeout: time.Second,
+		Retries: 2,
+		Backoff: time.Millisecond,
+	})
+}
+
+func TestGetByISBN(t *testing.T) {
+	as := assert.New(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		as.Equal("/api/v1/books", r.URL.Path)
+		as.Equal("978160309028,978160309029", r.URL.Query().Get("isbn"))
+		as.Equal(lookupFields, r.URL.Query().Get("fields"))
+		as.Equal("gck_test_secret", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusFound)
+		w.Write([]byte(`{"payload":[{"id":4,"title":"Dune","ISBN":"978160309028"}]}`))
+	}))
+	defer server.Close()
+
+	books, err := newRepository(server.URL).GetByISBN(context.Background(), "ISBN", []string{"978160309028", "978160309029"})
+	as.NoError(err)
+	as.Equal([]domain.Book{{ID: 4, Title: "Dune", ISBN: "978160309028"}}, books)
+	as.EqualValues(2, calls.Load())
+}
+
+func TestUnavailable(t *testing.T) {
+	as := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	_, err := newRepository(url).GetByISBN(context.Background(), "ISBN", []string{"978160309028"})
+	as.True(errors.Is(err, domain.ErrUnavailable), err)
+	as.Contains(err.Error(), url)
+}
+
+func TestCreateIsNotRetried(t *testing.T) {
+	as := assert.New(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := newRepository(server.URL).Create(context.Background(), &domain.Book{Title: "Dune"})
+	as.True(errors.Is(err, domain.ErrUnavailable), err)
+	as.EqualValues(1, calls.Load())
+}
+
+func TestStatusErrors(t *testing.T) {
+	as := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/books/1":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"record not found"}`))
+		case "/api/v1/books/filter":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer server.Close()
+	repo := newRepository(server.URL)
+
+	_, err := repo.Get(context.Background(), "1", domain.QueryOptions{})
+	as.True(errors.Is(err, domain.ErrRecordNotFound), err)
+
+	books, err := repo.GetByFilter(context.Background(), "title", "dune", domain.QueryOptions{})
+	as.NoError(err)
+	as.Empty(books)
+
+	err = repo.Create(context.Background(), &domain.Book{Title: "Dune"})
+	as.True(errors.Is(err, domain.ErrDuplicateRecord), err)
+}
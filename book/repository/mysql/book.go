@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type mysqlBookRepository struct {
@@ -20,7 +21,7 @@ func NewMySqlBookRepository(db *gorm.DB) domain.BookRepository {
 }
 
 func (m *mysqlBookRepository) Create(ctx context.Context, book *domain.Book) error {
-	err := m.db.WithContext(ctx).Create(book).Error
+	err := txn.DB(ctx, m.db).Create(book).Error
 	if err != nil {
 		if strings.Contains(err.Error(), "Duplicate") {
 			return domain.ErrDuplicateRecord
@@ -30,9 +31,9 @@ func (m *mysqlBookRepository) Create(ctx context.Context, book *domain.Book) err
 	return nil
 }
 
-func (m *mysqlBookRepository) Get(ctx context.Context, id string) (domain.Book, error) {
+func (m *mysqlBookRepository) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
 	var book domain.Book
-	err := m.db.WithContext(ctx).Preload(clause.Associations).Where("id = ?", id).Find(&book).Error
+	err := m.query(ctx, opts).Where("id = ?", id).First(&book).Error
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
@@ -45,19 +46,32 @@ func (m *mysqlBookRepository) Get(ctx context.Context, id string) (domain.Book,
 }
 
 func (m *mysqlBookRepository) Update(ctx context.Context, book *domain.Book, updatedBook domain.Book) error {
-	err := m.db.WithContext(ctx).Model(book).Updates(updatedBook).Error
+	err := txn.DB(ctx, m.db).Model(book).Updates(updatedBook).Error
 	return err
 }
 
+// Delete removes the book and bumps updated_at on its authors, whose
+// representation lists the book.
 func (m *mysqlBookRepository) Delete(ctx context.Context, id string, book *domain.Book) error {
-	err := m.db.WithContext(ctx).Where("id = ?", id).Delete(book).Error
+	var authorIDs []int
+	err := txn.DB(ctx, m.db).Model(&domain.AuthorBooks{}).Where("book_id = ?", id).Pluck("author_id", &authorIDs).Error
+	if err != nil {
+		return err
+	}
+	err = txn.DB(ctx, m.db).Where("id = ?", id).Delete(book).Error
+	if err != nil {
+		return err
+	}
+	if len(authorIDs) > 0 {
+		err = txn.DB(ctx, m.db).Model(&domain.Author{}).Where("id IN ?", authorIDs).UpdateColumn("updated_at", time.Now()).Error
+	}
 	return err
 }
 
-func (m *mysqlBookRepository) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Book, error) {
+func (m *mysqlBookRepository) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
 	var books []domain.Book
 	filterValue = "%" + filterValue + "%"
-	err := m.db.WithContext(ctx).Preload(clause.Associations).Where(fmt.Sprintf("%s LIKE ?", filter), filterValue).Find(&books).Error
+	err := m.query(ctx, opts).Where(fmt.Sprintf("%s LIKE ?", filter), filterValue).Find(&books).Error
 	if err != nil {
 		return []domain.Book{}, err
 	}
@@ -66,9 +80,39 @@ func (m *mysqlBookRepository) GetByFilter(ctx context.Context, filter, filterVal
 
 func (m *mysqlBookRepository) GetByISBN(ctx context.Context, field string, filter []string) ([]domain.Book, error) {
 	var books []domain.Book
-	err := m.db.WithContext(ctx).Where(fmt.Sprintf("%s IN ?", field), filter).Find(&books).Error
+	err := txn.DB(ctx, m.db).Where(fmt.Sprintf("%s IN ?", field), filter).Find(&books).Error
+	if err != nil {
+		return []domain.Book{}, err
+	}
+	return books, nil
+}
+
+// GetByIDs reads the books with the given ids in one query per level of
+// associations. Ids without a book are skipped.
+func (m *mysqlBookRepository) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	var books []domain.Book
+	if len(ids) == 0 {
+		return books, nil
+	}
+	err := m.query(ctx, opts).Where("id IN ?", ids).Find(&books).Error
 	if err != nil {
 		return []domain.Book{}, err
 	}
 	return books, nil
 }
+
+// query selects only the columns and preloads only the associations opts
+// asks for, and reads only the page it asks for.
+func (m *mysqlBookRepository) query(ctx context.Context, opts domain.QueryOptions) *gorm.DB {
+	tx := txn.DB(ctx, m.db)
+	if columns := opts.Columns(domain.BookColumns); columns != nil {
+		tx = tx.Select(columns)
+	}
+	if opts.Includes("authors") {
+		tx = tx.Preload(opts.Path("Authors", "BooksPublished"))
+	}
+	if opts.Limit > 0 {
+		tx = tx.Order("id").Limit(opts.Limit).Offset(opts.Offset)
+	}
+	return tx
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/authz"
+)
+
+type authorizedBookService struct {
+	next   domain.BookService
+	policy *authz.Policy
+}
+
+// NewAuthorizedBookService wraps b so that every method first checks the
+// caller's permission against policy.
+func NewAuthorizedBookService(b domain.BookService, policy *authz.Policy) domain.BookService {
+	return &authorizedBookService{next: b, policy: policy}
+}
+
+func (a *authorizedBookService) Create(ctx context.Context, book *domain.Book) error {
+	if err := a.policy.Authorize(ctx, authz.BooksCreate); err != nil {
+		return err
+	}
+	return a.next.Create(ctx, book)
+}
+
+func (a *authorizedBookService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	if err := a.policy.Authorize(ctx, authz.BooksRead); err != nil {
+		return domain.Book{}, err
+	}
+	return a.next.Get(ctx, id, opts)
+}
+
+func (a *authorizedBookService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	if err := a.policy.Authorize(ctx, authz.BooksRead); err != nil {
+		return nil, err
+	}
+	return a.next.GetByFilter(ctx, filter, filterValue, opts)
+}
+
+func (a *authorizedBookService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	if err := a.policy.Authorize(ctx, authz.BooksRead); err != nil {
+		return nil, err
+	}
+	return a.next.GetByIDs(ctx, ids, opts)
+}
+
+func (a *authorizedBookService) GetByISBNs(ctx context.Context, isbns []string) ([]domain.Book, error) {
+	if err := a.policy.Authorize(ctx, authz.BooksRead); err != nil {
+		return nil, err
+	}
+	return a.next.GetByISBNs(ctx, isbns)
+}
+
+func (a *authorizedBookService) Update(ctx context.Context, id string, book *domain.Book, updatedBook domain.Book) error {
+	if err := a.policy.Authorize(ctx, authz.BooksUpdate); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, id, book, updatedBook)
+}
+
+func (a *authorizedBookService) Delete(ctx context.Context, id string, book *domain.Book) error {
+	if err := a.policy.Authorize(ctx, authz.BooksDelete); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id, book)
+}
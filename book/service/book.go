@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/metrics"
+	"log/slog"
 )
 
 type bookService struct {
@@ -16,30 +19,52 @@ func NewBookService(b domain.BookRepository) domain.BookService {
 func (p *bookService) Create(ctx context.Context, book *domain.Book) error {
 
 	err := p.bookRepository.Create(ctx, book)
-	return err
+	if err != nil {
+		return err
+	}
+	metrics.BooksCreated.Inc()
+	slog.InfoContext(ctx, "book created", "layer", "service", "actor", auth.Subject(ctx), "book_id", book.ID, "isbn", book.ISBN)
+	return nil
 }
 
-func (p *bookService) Get(ctx context.Context, id string) (domain.Book, error) {
-	book, err := p.bookRepository.Get(ctx, id)
+func (p *bookService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	book, err := p.bookRepository.Get(ctx, id, opts)
 	return book, err
 }
 
-func (p *bookService) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Book, error) {
+func (p *bookService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
 
-	book, err := p.bookRepository.GetByFilter(ctx, filter, filterValue)
+	book, err := p.bookRepository.GetByFilter(ctx, filter, filterValue, opts)
+	slog.DebugContext(ctx, "book search", "layer", "service", "filter", filter, "matches", len(book))
 	if len(book) == 0 {
 		return book, domain.ErrBookNotFound
 	}
 	return book, err
 }
 
+func (p *bookService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	return p.bookRepository.GetByIDs(ctx, ids, opts)
+}
+
+func (p *bookService) GetByISBNs(ctx context.Context, isbns []string) ([]domain.Book, error) {
+	return p.bookRepository.GetByISBN(ctx, "ISBN", isbns)
+}
+
 func (p *bookService) Update(ctx context.Context, id string, book *domain.Book, updatedBook domain.Book) error {
 
 	err := p.bookRepository.Update(ctx, book, updatedBook)
-	return err
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "book updated", "layer", "service", "actor", auth.Subject(ctx), "book_id", id)
+	return nil
 }
 
 func (p *bookService) Delete(ctx context.Context, id string, book *domain.Book) error {
 	err := p.bookRepository.Delete(ctx, id, book)
-	return err
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "book deleted", "layer", "service", "actor", auth.Subject(ctx), "book_id", id)
+	return nil
 }
@@ -44,32 +44,32 @@ func TestGet(t *testing.T) {
 	bookRepo := &repository.BookRepositoryMock{}
 	id := "1"
 	t.Run("happy path: Successfully fetches a book", func(t *testing.T) {
-		bookRepo.On("Get", context.Background(), id).Return(domain.Book{
+		bookRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Book{
 			ID:          1,
 			ISBN:        "978160309028",
 			Title:       "About test",
 			Description: "How to write unit test",
 		}, nil).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.Get(context.Background(), id)
+		book, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.NoError(err)
 		as.Equal("978160309028", book.ISBN)
 		bookRepo.AssertExpectations(t)
 	})
 
 	t.Run("input error: Book not found", func(t *testing.T) {
-		bookRepo.On("Get", context.Background(), id).Return(domain.Book{}, domain.ErrRecordNotFound).Once()
+		bookRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Book{}, domain.ErrRecordNotFound).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.Get(context.Background(), id)
+		book, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal("", book.ISBN)
 		bookRepo.AssertExpectations(t)
 	})
 
 	t.Run("system error: Database failed", func(t *testing.T) {
-		bookRepo.On("Get", context.Background(), id).Return(domain.Book{}, errors.New("Something failed")).Once()
+		bookRepo.On("Get", context.Background(), id, domain.QueryOptions{}).Return(domain.Book{}, errors.New("Something failed")).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.Get(context.Background(), id)
+		book, err := service.Get(context.Background(), id, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal("", book.ISBN)
 		bookRepo.AssertExpectations(t)
@@ -81,7 +81,7 @@ func TestGetByFilter(t *testing.T) {
 	bookRepo := &repository.BookRepositoryMock{}
 	filter, filterValue := "title", "test"
 	t.Run("happy path: Successfully fetches book by filter", func(t *testing.T) {
-		bookRepo.On("GetByFilter", context.Background(), filter, filterValue).Return([]domain.Book{
+		bookRepo.On("GetByFilter", context.Background(), filter, filterValue, domain.QueryOptions{}).Return([]domain.Book{
 			{
 				ID:          1,
 				ISBN:        "978160309028",
@@ -96,25 +96,25 @@ func TestGetByFilter(t *testing.T) {
 			},
 		}, nil).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.GetByFilter(context.Background(), filter, filterValue)
+		book, err := service.GetByFilter(context.Background(), filter, filterValue, domain.QueryOptions{})
 		as.NoError(err)
 		as.Equal(len(book), 2)
 		bookRepo.AssertExpectations(t)
 	})
 
 	t.Run("input error: Book matches not found", func(t *testing.T) {
-		bookRepo.On("GetByFilter", context.Background(), filter, filterValue).Return([]domain.Book{}, domain.ErrBookNotFound).Once()
+		bookRepo.On("GetByFilter", context.Background(), filter, filterValue, domain.QueryOptions{}).Return([]domain.Book{}, domain.ErrBookNotFound).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.GetByFilter(context.Background(), filter, filterValue)
+		book, err := service.GetByFilter(context.Background(), filter, filterValue, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal(len(book), 0)
 		bookRepo.AssertExpectations(t)
 	})
 
 	t.Run("system error: Database failed", func(t *testing.T) {
-		bookRepo.On("GetByFilter", context.Background(), filter, filterValue).Return([]domain.Book{}, errors.New("Something failed")).Once()
+		bookRepo.On("GetByFilter", context.Background(), filter, filterValue, domain.QueryOptions{}).Return([]domain.Book{}, errors.New("Something failed")).Once()
 		service := NewBookService(bookRepo)
-		book, err := service.GetByFilter(context.Background(), filter, filterValue)
+		book, err := service.GetByFilter(context.Background(), filter, filterValue, domain.QueryOptions{})
 		as.Error(err)
 		as.Equal(len(book), 0)
 		bookRepo.AssertExpectations(t)
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"geniuscrew/domain"
+	"geniuscrew/internal/cache"
+	"geniuscrew/internal/metrics"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+type cachedBookService struct {
+	next  domain.BookService
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedBookService wraps b with a read-through cache of reads at most one
+// association level deep. Writes invalidate the book, every cached search and
+// the cached authors of the book, whose representation embeds it.
+func NewCachedBookService(b domain.BookService, store cache.Store, ttl time.Duration) domain.BookService {
+	return &cachedBookService{next: b, store: store, ttl: ttl}
+}
+
+func (s *cachedBookService) Create(ctx context.Context, book *domain.Book) error {
+	err := s.next.Create(ctx, book)
+	if err != nil {
+		return err
+	}
+	cache.DeleteVariants(ctx, s.store, cache.BookKey(strconv.Itoa(book.ID)))
+	s.store.DeletePrefix(ctx, cache.BookSearchPrefix)
+	return nil
+}
+
+func (s *cachedBookService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	if opts.Depth > cache.MaxDepth {
+		return s.next.Get(ctx, id, opts)
+	}
+	var book domain.Book
+	key := cache.Variant(cache.BookKey(id), opts.Key())
+	if s.load(ctx, key, &book) {
+		return book, nil
+	}
+	book, err := s.next.Get(ctx, id, opts)
+	if err != nil {
+		return book, err
+	}
+	s.save(ctx, key, book)
+	return book, nil
+}
+
+func (s *cachedBookService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	if opts.Depth > cache.MaxDepth {
+		return s.next.GetByFilter(ctx, filter, filterValue, opts)
+	}
+	var books []domain.Book
+	key := cache.Variant(cache.BookSearchKey(filter, filterValue), opts.Key())
+	if s.load(ctx, key, &books) {
+		return books, nil
+	}
+	books, err := s.next.GetByFilter(ctx, filter, filterValue, opts)
+	if err != nil {
+		return books, err
+	}
+	s.save(ctx, key, books)
+	return books, nil
+}
+
+// GetByIDs is not cached: batches rarely repeat, and one entry per batch
+// would multiply what a write has to invalidate.
+func (s *cachedBookService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	return s.next.GetByIDs(ctx, ids, opts)
+}
+
+// GetByISBNs is not cached for the same reason as GetByIDs.
+func (s *cachedBookService) GetByISBNs(ctx context.Context, isbns []string) ([]domain.Book, error) {
+	return s.next.GetByISBNs(ctx, isbns)
+}
+
+func (s *cachedBookService) Update(ctx context.Context, id string, book *domain.Book, updatedBook domain.Book) error {
+	err := s.next.Update(ctx, id, book, updatedBook)
+	s.invalidate(ctx, id, book.Authors)
+	return err
+}
+
+func (s *cachedBookService) Delete(ctx context.Context, id string, book *domain.Book) error {
+	// The caller's book is usually empty; look up the authors to invalidate.
+	current, lookupErr := s.next.Get(ctx, id, domain.QueryOptions{})
+	err := s.next.Delete(ctx, id, book)
+	if lookupErr != nil {
+		s.store.DeletePrefix(ctx, cache.AuthorKey(""))
+	}
+	s.invalidate(ctx, id, current.Authors)
+	return err
+}
+
+// invalidate drops everything that may embed book id. It also runs after a
+// failed write, which may have been applied partially.
+func (s *cachedBookService) invalidate(ctx context.Context, id string, authors []domain.Author) {
+	keys := []string{cache.BookKey(id)}
+	for _, author := range authors {
+		keys = append(keys, cache.AuthorKey(strconv.Itoa(author.ID)))
+	}
+	cache.DeleteVariants(ctx, s.store, keys...)
+	s.store.DeletePrefix(ctx, cache.BookSearchPrefix)
+	s.store.DeletePrefix(ctx, cache.AuthorSearchPrefix)
+}
+
+func (s *cachedBookService) load(ctx context.Context, key string, v interface{}) bool {
+	data, ok := s.store.Get(ctx, key)
+	if ok && json.Unmarshal(data, v) == nil {
+		metrics.CacheHits.WithLabelValues("books").Inc()
+		return true
+	}
+	metrics.CacheMisses.WithLabelValues("books").Inc()
+	return false
+}
+
+func (s *cachedBookService) save(ctx context.Context, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.WarnContext(ctx, "caching book failed", "layer", "service", "key", key, "error", err)
+		return
+	}
+	s.store.Set(ctx, key, data, s.ttl)
+}
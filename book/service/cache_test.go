@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCachedBookService(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	id := "1"
+	book := domain.Book{ID: 1, ISBN: "978160309028", Authors: []domain.Author{{ID: 7, Name: "John Doe"}}}
+
+	t.Run("happy path: second read is served from the cache", func(t *testing.T) {
+		bookRepo := &repository.BookRepositoryMock{}
+		bookRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(book, nil).Once()
+		service := NewCachedBookService(NewBookService(bookRepo), cache.NewLRU(10, 0), time.Minute)
+		first, err := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(err)
+		second, err := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(err)
+		as.Equal(first.ISBN, second.ISBN)
+		as.Equal("John Doe", second.Authors[0].Name)
+		bookRepo.AssertExpectations(t)
+	})
+
+	t.Run("update invalidates the book, searches and its authors", func(t *testing.T) {
+		bookRepo := &repository.BookRepositoryMock{}
+		store := cache.NewLRU(10, 0)
+		store.Set(ctx, cache.AuthorKey("7"), []byte(`{}`), time.Minute)
+		store.Set(ctx, cache.AuthorKey("8"), []byte(`{}`), time.Minute)
+		store.Set(ctx, cache.BookSearchKey("title", "test"), []byte(`[]`), time.Minute)
+		bookRepo.On("Get", ctx, id, domain.QueryOptions{}).Return(book, nil).Twice()
+		bookRepo.On("Update", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+		service := NewCachedBookService(NewBookService(bookRepo), store, time.Minute)
+
+		current, _ := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(service.Update(ctx, id, &current, domain.Book{Title: "New"}))
+		_, ok := store.Get(ctx, cache.AuthorKey("7"))
+		as.False(ok)
+		_, ok = store.Get(ctx, cache.AuthorKey("8"))
+		as.True(ok)
+		_, ok = store.Get(ctx, cache.BookSearchKey("title", "test"))
+		as.False(ok)
+		_, err := service.Get(ctx, id, domain.QueryOptions{})
+		as.NoError(err)
+		bookRepo.AssertExpectations(t)
+	})
+
+	t.Run("deeper reads are not cached", func(t *testing.T) {
+		bookRepo := &repository.BookRepositoryMock{}
+		deep := domain.QueryOptions{Depth: 2}
+		bookRepo.On("Get", ctx, id, deep).Return(book, nil).Twice()
+		store := cache.NewLRU(10, 0)
+		service := NewCachedBookService(NewBookService(bookRepo), store, time.Minute)
+		for i := 0; i < 2; i++ {
+			_, err := service.Get(ctx, id, deep)
+			as.NoError(err)
+		}
+		_, ok := store.Get(ctx, cache.Variant(cache.BookKey(id), deep.Key()))
+		as.False(ok)
+		bookRepo.AssertExpectations(t)
+	})
+}
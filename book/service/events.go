@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"strconv"
+)
+
+type publishingBookService struct {
+	next       domain.BookService
+	transactor domain.Transactor
+	publisher  domain.EventPublisher
+	mapper     v1.Mapper
+}
+
+// NewPublishingBookService wraps b so that every write publishes a
+// book.created, book.updated or book.deleted event in the same transaction:
+// with an outbox publisher, the event is recorded if and only if the write
+// is committed, and a write fails if its event cannot be recorded.
+func NewPublishingBookService(b domain.BookService, transactor domain.Transactor, publisher domain.EventPublisher, mapper v1.Mapper) domain.BookService {
+	return &publishingBookService{next: b, transactor: transactor, publisher: publisher, mapper: mapper}
+}
+
+func (s *publishingBookService) Create(ctx context.Context, book *domain.Book) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Create(ctx, book)
+		if err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventBookCreated, bookAggregate(book.ID), s.mapper.Book(*book)))
+	})
+}
+
+func (s *publishingBookService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	return s.next.Get(ctx, id, opts)
+}
+
+func (s *publishingBookService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	return s.next.GetByFilter(ctx, filter, filterValue, opts)
+}
+
+func (s *publishingBookService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	return s.next.GetByIDs(ctx, ids, opts)
+}
+
+func (s *publishingBookService) GetByISBNs(ctx context.Context, isbns []string) ([]domain.Book, error) {
+	return s.next.GetByISBNs(ctx, isbns)
+}
+
+func (s *publishingBookService) Update(ctx context.Context, id string, book *domain.Book, updatedBook domain.Book) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Update(ctx, id, book, updatedBook)
+		if err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventBookUpdated, bookAggregate(book.ID), s.mapper.Book(*book)))
+	})
+}
+
+func (s *publishingBookService) Delete(ctx context.Context, id string, book *domain.Book) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.next.Delete(ctx, id, book)
+		if err != nil {
+			return err
+		}
+		bookID, _ := strconv.Atoi(id)
+		return s.publisher.Publish(ctx, domain.NewEvent(domain.EventBookDeleted, bookAggregate(bookID), v1.Deleted{ID: bookID}))
+	})
+}
+
+func bookAggregate(id int) string {
+	return "book/" + strconv.Itoa(id)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedBookService struct {
+	next domain.BookService
+}
+
+// NewTracedBookService wraps b so that every method runs in its own span.
+func NewTracedBookService(b domain.BookService) domain.BookService {
+	return &tracedBookService{next: b}
+}
+
+func (t *tracedBookService) Create(ctx context.Context, book *domain.Book) error {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.Create")
+	defer span.End()
+	err := t.next.Create(ctx, book)
+	return tracing.End(span, err)
+}
+
+func (t *tracedBookService) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.Get", trace.WithAttributes(attribute.String("book.id", id), attribute.String("query", opts.Key())))
+	defer span.End()
+	book, err := t.next.Get(ctx, id, opts)
+	return book, tracing.End(span, err)
+}
+
+func (t *tracedBookService) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.GetByFilter", trace.WithAttributes(attribute.String("filter", filter), attribute.String("query", opts.Key())))
+	defer span.End()
+	books, err := t.next.GetByFilter(ctx, filter, filterValue, opts)
+	span.SetAttributes(attribute.Int("results", len(books)))
+	return books, tracing.End(span, err)
+}
+
+func (t *tracedBookService) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.GetByIDs", trace.WithAttributes(attribute.Int("ids", len(ids)), attribute.String("query", opts.Key())))
+	defer span.End()
+	books, err := t.next.GetByIDs(ctx, ids, opts)
+	span.SetAttributes(attribute.Int("results", len(books)))
+	return books, tracing.End(span, err)
+}
+
+func (t *tracedBookService) GetByISBNs(ctx context.Context, isbns []string) ([]domain.Book, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.GetByISBNs", trace.WithAttributes(attribute.Int("isbns", len(isbns))))
+	defer span.End()
+	books, err := t.next.GetByISBNs(ctx, isbns)
+	span.SetAttributes(attribute.Int("results", len(books)))
+	return books, tracing.End(span, err)
+}
+
+func (t *tracedBookService) Update(ctx context.Context, id string, book *domain.Book, updatedBook domain.Book) error {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.Update", trace.WithAttributes(attribute.String("book.id", id)))
+	defer span.End()
+	err := t.next.Update(ctx, id, book, updatedBook)
+	return tracing.End(span, err)
+}
+
+func (t *tracedBookService) Delete(ctx context.Context, id string, book *domain.Book) error {
+	ctx, span := tracing.Tracer().Start(ctx, "BookService.Delete", trace.WithAttributes(attribute.String("book.id", id)))
+	defer span.End()
+	err := t.next.Delete(ctx, id, book)
+	return tracing.End(span, err)
+}
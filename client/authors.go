@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	v1 "geniuscrew/api/v1"
+)
+
+// AuthorField is a field authors can be searched by.
+type AuthorField string
+
+const (
+	AuthorName    AuthorField = "name"
+	AuthorSurname AuthorField = "surname"
+	AuthorEmail   AuthorField = "email"
+)
+
+// CreateAuthor creates an author linked to the books with the ISBNs in
+// BooksPublished, at least one of which must exist.
+func (c *Client) CreateAuthor(ctx context.Context, author v1.CreateAuthorRequest) (v1.Author, error) {
+	var out struct {
+		Payload v1.Author `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodPost, "/api/v1/authors", nil, author, &out)
+	return out.Payload, err
+}
+
+// GetAuthor reads the author with id.
+func (c *Client) GetAuthor(ctx context.Context, id int, opts ...ReadOption) (v1.Author, error) {
+	var out struct {
+		Payload v1.Author `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodGet, authorPath(id), queryOf(opts), nil, &out)
+	return out.Payload, err
+}
+
+// SearchAuthors returns the authors whose field contains value. The API
+// answers 404, which matches domain.ErrRecordNotFound, when none does.
+func (c *Client) SearchAuthors(ctx context.Context, field AuthorField, value string, opts ...ReadOption) ([]v1.Author, error) {
+	query := queryOf(opts)
+	query.Set("field", string(field))
+	query.Set("value", value)
+	var out struct {
+		Payload []v1.Author `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodGet, "/api/v1/authors/filter", query, nil, &out)
+	return out.Payload, err
+}
+
+// SearchAuthorsIter pages through the authors whose field contains value,
+// pageSize at a time.
+func (c *Client) SearchAuthorsIter(field AuthorField, value string, pageSize int, opts ...ReadOption) *Iterator[v1.Author] {
+	return newIterator(pageSize, func(ctx context.Context, limit, offset int) ([]v1.Author, error) {
+		return c.SearchAuthors(ctx, field, value, append(opts, Page(limit, offset))...)
+	})
+}
+
+// UpdateAuthor changes the non-empty fields of update. Like the API, it
+// replaces the author's books with BooksPublished, so leaving it nil unlinks
+// them all; the API rejects an empty, non-nil list. Use LinkBooks and
+// UnlinkBooks to change only some.
+func (c *Client) UpdateAuthor(ctx context.Context, id int, update v1.UpdateAuthorRequest) error {
+	return c.do(ctx, http.MethodPut, authorPath(id), nil, update, nil)
+}
+
+// DeleteAuthor deletes the author with id and their links to books.
+func (c *Client) DeleteAuthor(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, authorPath(id), nil, nil, nil)
+}
+
+// SetAuthorBooks replaces the books linked to the author with those with the
+// given ISBNs; no ISBNs unlink every book. The API rejects an empty list, so
+// that case is sent as null.
+func (c *Client) SetAuthorBooks(ctx context.Context, id int, isbns []string) error {
+	if len(isbns) == 0 {
+		isbns = nil
+	}
+	return c.UpdateAuthor(ctx, id, v1.UpdateAuthorRequest{BooksPublished: isbns})
+}
+
+// LinkBooks links the author to the books with the given ISBNs on top of
+// those already linked. It reads the current links first, so concurrent
+// changes to the same author can be lost.
+func (c *Client) LinkBooks(ctx context.Context, id int, isbns ...string) error {
+	linked, err := c.linkedISBNs(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, isbn := range isbns {
+		if !contains(linked, isbn) {
+			linked = append(linked, isbn)
+		}
+	}
+	return c.SetAuthorBooks(ctx, id, linked)
+}
+
+// UnlinkBooks removes the links of the author to the books with the given
+// ISBNs, with the same caveat as LinkBooks.
+func (c *Client) UnlinkBooks(ctx context.Context, id int, isbns ...string) error {
+	linked, err := c.linkedISBNs(ctx, id)
+	if err != nil {
+		return err
+	}
+	kept := linked[:0]
+	for _, isbn := range linked {
+		if !contains(isbns, isbn) {
+			kept = append(kept, isbn)
+		}
+	}
+	return c.SetAuthorBooks(ctx, id, kept)
+}
+
+func (c *Client) linkedISBNs(ctx context.Context, id int) ([]string, error) {
+	author, err := c.GetAuthor(ctx, id, Fields("id", "books_published"))
+	if err != nil {
+		return nil, err
+	}
+	isbns := make([]string, 0, len(author.BooksPublished))
+	for _, book := range author.BooksPublished {
+		isbns = append(isbns, book.ISBN)
+	}
+	return isbns, nil
+}
+
+func authorPath(id int) string {
+	return "/api/v1/authors/" + strconv.Itoa(id)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
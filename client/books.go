@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v1 "geniuscrew/api/v1"
+)
+
+// BookField is a field books can be searched by.
+type BookField string
+
+const (
+	BookTitle       BookField = "title"
+	BookDescription BookField = "description"
+)
+
+// CreateBook creates a book and returns it as stored.
+func (c *Client) CreateBook(ctx context.Context, book v1.CreateBookRequest) (v1.Book, error) {
+	var out struct {
+		Payload v1.Book `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodPost, "/api/v1/books", nil, book, &out)
+	return out.Payload, err
+}
+
+// GetBook reads the book with id.
+func (c *Client) GetBook(ctx context.Context, id int, opts ...ReadOption) (v1.Book, error) {
+	var out struct {
+		Payload v1.Book `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodGet, "/api/v1/books/"+strconv.Itoa(id), queryOf(opts), nil, &out)
+	return out.Payload, err
+}
+
+// GetBooks reads the books with the given ids, leaving out ids without a
+// book. At most 100 ids can be read at once.
+func (c *Client) GetBooks(ctx context.Context, ids []int, opts ...ReadOption) ([]v1.Book, error) {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	query := queryOf(opts)
+	query.Set("id", strings.Join(values, ","))
+	return c.listBooks(ctx, "/api/v1/books", query)
+}
+
+// GetBooksByISBN reads the books with the given ISBNs, leaving out ISBNs
+// without a book. At most 100 ISBNs can be read at once.
+func (c *Client) GetBooksByISBN(ctx context.Context, isbns []string, opts ...ReadOption) ([]v1.Book, error) {
+	query := queryOf(opts)
+	query.Set("isbn", strings.Join(isbns, ","))
+	return c.listBooks(ctx, "/api/v1/books", query)
+}
+
+// SearchBooks returns the books whose field contains value. The API answers
+// 404, which matches domain.ErrBookNotFound, when none does.
+func (c *Client) SearchBooks(ctx context.Context, field BookField, value string, opts ...ReadOption) ([]v1.Book, error) {
+	query := queryOf(opts)
+	query.Set("field", string(field))
+	query.Set("value", value)
+	return c.listBooks(ctx, "/api/v1/books/filter", query)
+}
+
+// SearchBooksIter pages through the books whose field contains value,
+// pageSize at a time.
+func (c *Client) SearchBooksIter(field BookField, value string, pageSize int, opts ...ReadOption) *Iterator[v1.Book] {
+	return newIterator(pageSize, func(ctx context.Context, limit, offset int) ([]v1.Book, error) {
+		return c.SearchBooks(ctx, field, value, append(opts, Page(limit, offset))...)
+	})
+}
+
+// UpdateBook changes the non-empty fields of update and returns the book.
+func (c *Client) UpdateBook(ctx context.Context, id int, update v1.UpdateBookRequest) (v1.Book, error) {
+	var out v1.Book
+	err := c.do(ctx, http.MethodPut, "/api/v1/books/"+strconv.Itoa(id), nil, update, &out)
+	return out, err
+}
+
+// DeleteBook deletes the book with id.
+func (c *Client) DeleteBook(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/books/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+func (c *Client) listBooks(ctx context.Context, path string, query url.Values) ([]v1.Book, error) {
+	var out struct {
+		Payload []v1.Book `json:"payload"`
+	}
+	err := c.do(ctx, http.MethodGet, path, query, nil, &out)
+	return out.Payload, err
+}
@@ -0,0 +1,273 @@
+// Package client is a typed Go client for the catalog REST API. Requests and
+// responses use the representations of api/v1, and error responses are
+// decoded into *Error values that errors.Is matches against the domain
+// sentinels, e.g.
+//
+//	c := client.New("http://localhost:8080", client.WithAPIKey(key))
+//	book, err := c.GetBook(ctx, 42)
+//	if errors.Is(err, domain.ErrRecordNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"geniuscrew/domain"
+)
+
+// Client calls one catalog API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	header  http.Header
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	// retryCreates extends the retries to POST requests
+	retryCreates bool
+	editors      []func(*http.Request)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithAPIKey authenticates every request with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.header.Set("X-API-Key", key) }
+}
+
+// WithBearerToken authenticates every request with a JWT.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.header.Set("Authorization", "Bearer "+token) }
+}
+
+// WithHeader adds a header to every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) { c.header.Add(key, value) }
+}
+
+// WithTimeout bounds each attempt of a request, on top of the deadline of
+// its context. Zero, the default, leaves attempts unbounded.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRetries makes reads, updates and deletes that fail because the API is
+// unreachable or answers 429, 502, 503 or 504 be attempted again up to n
+// times, waiting backoff before the first retry and doubling it after each.
+// A Retry-After header on the response takes precedence over the backoff.
+// Creates are only retried with WithRetriedCreates. The default is no
+// retries.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// WithRetriedCreates makes WithRetries apply to creates too. Every attempt of
+// a create carries the same Idempotency-Key, so the API makes it once and
+// answers a retry with the first response; a retry that finds the first
+// attempt still running is retried too. This is only safe against an API
+// that keeps those responses: one started with IDEMPOTENCY_TTL=0, or whose
+// idempotency store is failing, makes a create once per attempt.
+func WithRetriedCreates() Option {
+	return func(c *Client) { c.retryCreates = true }
+}
+
+// WithRequestEditor calls edit on every outgoing request, e.g. to propagate
+// trace context from the request's context.
+func WithRequestEditor(edit func(*http.Request)) Option {
+	return func(c *Client) { c.editors = append(c.editors, edit) }
+}
+
+// New returns a client of the API at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		header:  http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ReadOption narrows what a read returns.
+type ReadOption func(url.Values)
+
+// Fields returns only the named fields, as the fields query parameter does.
+func Fields(names ...string) ReadOption {
+	return func(q url.Values) { q.Set("fields", strings.Join(names, ",")) }
+}
+
+// Include loads only the named associations.
+func Include(names ...string) ReadOption {
+	return func(q url.Values) { q.Set("include", strings.Join(names, ",")) }
+}
+
+// Page returns at most limit results of a search, skipping offset of them.
+func Page(limit, offset int) ReadOption {
+	return func(q url.Values) {
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+	}
+}
+
+func queryOf(opts []ReadOption) url.Values {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+	return query
+}
+
+// do sends a request, retrying it when allowed, and decodes the JSON
+// response into out unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	header := http.Header{}
+	if method == http.MethodPost {
+		header.Set(idempotencyKeyHeader, newIdempotencyKey())
+	}
+	attempts := 1
+	if method != http.MethodPost || c.retryCreates {
+		attempts += max(c.retries, 0)
+	}
+	backoff := c.backoff
+	var wait time.Duration
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		res, data, err := c.send(ctx, method, target, header, payload)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			wait, backoff = backoff, backoff*2
+			continue
+		}
+		if (retryable(res.StatusCode) || inProgress(res)) && attempt < attempts {
+			lastErr = decodeError(res.StatusCode, data)
+			wait, backoff = retryAfter(res, backoff), backoff*2
+			continue
+		}
+		if res.StatusCode >= http.StatusBadRequest {
+			return decodeError(res.StatusCode, data)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("decoding %s %s response: %w", method, path, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s: %v", domain.ErrUnavailable, c.baseURL, lastErr)
+}
+
+// send makes one attempt, bounded by the configured timeout.
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, payload []byte) (*http.Response, []byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, edit := range c.editors {
+		edit(req)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, data, nil
+}
+
+// retryable reports whether a response status means the API could not
+// handle the request right now.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// inProgress reports whether res says an earlier attempt with the same
+// Idempotency-Key is still running.
+func inProgress(res *http.Response) bool {
+	return res.StatusCode == http.StatusConflict && res.Header.Get("Retry-After") != ""
+}
+
+// idempotencyKeyHeader names the header the API recognizes retried creates by.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+func newIdempotencyKey() string {
+	key := make([]byte, 16)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+// retryAfter returns the wait a Retry-After header in seconds asks for, or
+// backoff when there is none.
+func retryAfter(res *http.Response, backoff time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return backoff
+}
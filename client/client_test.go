@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	as := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/books/1":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"record not found"}`))
+		case "/api/v1/books/filter":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"book not found"}`))
+		case "/api/v1/books":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"duplicate record"}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error":{"email":"email"}}`))
+		}
+	}))
+	defer server.Close()
+	c := New(server.URL)
+
+	_, err := c.GetBook(context.Background(), 1)
+	as.True(errors.Is(err, domain.ErrRecordNotFound), err)
+
+	_, err = c.SearchBooks(context.Background(), BookTitle, "dune")
+	as.True(errors.Is(err, domain.ErrBookNotFound), err)
+
+	_, err = c.CreateBook(context.Background(), v1.CreateBookRequest{Title: "Dune"})
+	as.True(errors.Is(err, domain.ErrDuplicateRecord), err)
+
+	err = c.UpdateAuthor(context.Background(), 1, v1.UpdateAuthorRequest{Email: "nope"})
+	var apiErr *Error
+	as.True(errors.As(err, &apiErr))
+	as.Equal(http.StatusUnprocessableEntity, apiErr.StatusCode)
+	as.Equal(map[string]string{"email": "email"}, apiErr.Fields)
+}
+
+func TestRetries(t *testing.T) {
+	as := assert.New(t)
+	var calls atomic.Int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+		}
+		if calls.Add(1) == 1 || r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		as.Equal("gck_test_secret", r.Header.Get("X-API-Key"))
+		as.Equal("title,ISBN", r.URL.Query().Get("fields"))
+		w.WriteHeader(http.StatusFound)
+		w.Write([]byte(`{"payload":{"id":4,"title":"Dune"}}`))
+	}))
+	defer server.Close()
+	c := New(server.URL, WithAPIKey("gck_test_secret"), WithRetries(2, time.Millisecond))
+
+	book, err := c.GetBook(context.Background(), 4, Fields("title", "ISBN"))
+	as.NoError(err)
+	as.Equal(v1.Book{ID: 4, Title: "Dune"}, book)
+	as.EqualValues(2, calls.Load())
+
+	// creates are not retried unless asked for
+	calls.Store(0)
+	_, err = c.CreateBook(context.Background(), v1.CreateBookRequest{Title: "Dune"})
+	as.True(errors.Is(err, domain.ErrUnavailable), err)
+	as.EqualValues(1, calls.Load())
+
+	calls.Store(0)
+	keys = nil
+	c = New(server.URL, WithAPIKey("gck_test_secret"), WithRetries(2, time.Millisecond), WithRetriedCreates())
+	_, err = c.CreateBook(context.Background(), v1.CreateBookRequest{Title: "Dune"})
+	as.True(errors.Is(err, domain.ErrUnavailable), err)
+	as.EqualValues(3, calls.Load())
+	as.Len(keys, 3)
+	as.NotEmpty(keys[0])
+	as.Equal(keys[0], keys[2])
+}
+
+func TestUnavailable(t *testing.T) {
+	as := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	err := New(url, WithRetries(1, time.Millisecond)).DeleteBook(context.Background(), 1)
+	as.True(errors.Is(err, domain.ErrUnavailable), err)
+}
+
+func TestSearchIter(t *testing.T) {
+	as := assert.New(t)
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		as.Equal("2", r.URL.Query().Get("limit"))
+		var authors []v1.Author
+		for id := offset + 1; id <= min(offset+2, 4); id++ {
+			authors = append(authors, v1.Author{ID: id})
+		}
+		if len(authors) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"no author matches"}`))
+			return
+		}
+		w.WriteHeader(http.StatusFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"payload": authors})
+	}))
+	defer server.Close()
+
+	var ids []int
+	it := New(server.URL).SearchAuthorsIter(AuthorName, "frank", 2)
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Value().ID)
+	}
+	as.NoError(it.Err())
+	as.Equal([]int{1, 2, 3, 4}, ids)
+	as.Equal([]string{"0", "2", "4"}, offsets)
+}
+
+func TestLinkBooks(t *testing.T) {
+	as := assert.New(t)
+	var linked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		as.Equal("/api/v1/authors/7", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusFound)
+			w.Write([]byte(`{"payload":{"id":7,"books_published":[{"id":1,"ISBN":"978160309028"},{"id":2,"ISBN":"978160309029"}]}}`))
+		case http.MethodPut:
+			var update v1.UpdateAuthorRequest
+			as.NoError(json.NewDecoder(r.Body).Decode(&update))
+			if inputErr := appvalidator.InputValidator(update); inputErr != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(map[string]interface{}{"error": inputErr})
+				return
+			}
+			linked = update.BooksPublished
+			w.Write([]byte(`{"message":"author updated"}`))
+		}
+	}))
+	defer server.Close()
+	c := New(server.URL)
+
+	as.NoError(c.LinkBooks(context.Background(), 7, "978160309029", "978160309030"))
+	as.Equal([]string{"978160309028", "978160309029", "978160309030"}, linked)
+
+	as.NoError(c.UnlinkBooks(context.Background(), 7, "978160309028"))
+	as.Equal([]string{"978160309029"}, linked)
+
+	as.NoError(c.UnlinkBooks(context.Background(), 7, "978160309028", "978160309029"))
+	as.Nil(linked)
+}
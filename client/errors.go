@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"geniuscrew/domain"
+)
+
+// Error is an error response of the API. It unwraps to the domain sentinel
+// the status and message stand for, if any, so that
+// errors.Is(err, domain.ErrRecordNotFound) holds for a 404.
+type Error struct {
+	StatusCode int
+	// Message is the error of the response body.
+	Message string
+	// Fields holds the failed fields of a validation error, keyed by field.
+	Fields map[string]string
+	err    error
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("%d %s: invalid fields %v", e.StatusCode, http.StatusText(e.StatusCode), e.Fields)
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// sentinels lists, by status, the errors a response may stand for. The first
+// one whose text the message contains wins, and the last one is the default.
+var sentinels = map[int][]error{
+	http.StatusNotFound:            {domain.ErrBookNotFound, domain.ErrRecordNotFound},
+	http.StatusConflict:            {domain.ErrDuplicateRecord},
+	http.StatusUnauthorized:        {domain.ErrUnauthorized},
+	http.StatusForbidden:           {domain.ErrForbidden},
+	http.StatusUnprocessableEntity: {domain.ErrUnknownField, domain.ErrInvalidPage, nil},
+	http.StatusServiceUnavailable:  {domain.ErrUnavailable},
+	http.StatusGatewayTimeout:      {context.DeadlineExceeded},
+}
+
+// decodeError builds the Error of a response. The body is {"error": ...}
+// where the error is a message or, for validation errors, an object of
+// messages by field.
+func decodeError(status int, data []byte) *Error {
+	e := &Error{StatusCode: status}
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	switch {
+	case json.Unmarshal(data, &body) != nil || body.Error == nil:
+		e.Message = strings.TrimSpace(string(data))
+	case json.Unmarshal(body.Error, &e.Message) == nil:
+	case json.Unmarshal(body.Error, &e.Fields) == nil:
+	default:
+		e.Message = string(body.Error)
+	}
+	if e.Message == "" && len(e.Fields) == 0 {
+		e.Message = http.StatusText(status)
+	}
+	candidates := sentinels[status]
+	for i, sentinel := range candidates {
+		if sentinel != nil && (i == len(candidates)-1 || strings.Contains(e.Message, sentinel.Error())) {
+			e.err = sentinel
+			break
+		}
+	}
+	return e
+}
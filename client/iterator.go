@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"geniuscrew/domain"
+)
+
+// Iterator pages through the results of a search:
+//
+//	it := c.SearchBooksIter(client.BookTitle, "go", 50)
+//	for it.Next(ctx) {
+//		book := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// A search without matches yields nothing and no error.
+type Iterator[T any] struct {
+	fetch    func(ctx context.Context, limit, offset int) ([]T, error)
+	pageSize int
+	page     []T
+	next     int
+	offset   int
+	done     bool
+	value    T
+	err      error
+}
+
+// newIterator returns an iterator reading pages of pageSize, clamped to
+// between 1 and domain.MaxLimit, through fetch.
+func newIterator[T any](pageSize int, fetch func(ctx context.Context, limit, offset int) ([]T, error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, pageSize: min(max(pageSize, 1), domain.MaxLimit)}
+}
+
+// Next advances to the next result, fetching the next page when the current
+// one is used up. It returns false when the results are exhausted or a fetch
+// failed.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.next >= len(it.page) {
+		if it.done || it.err != nil {
+			return false
+		}
+		page, err := it.fetch(ctx, it.pageSize, it.offset)
+		if errors.Is(err, domain.ErrRecordNotFound) || errors.Is(err, domain.ErrBookNotFound) {
+			// searches answer 404 past the last match
+			it.done = true
+			return false
+		}
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.next = page, 0
+		it.offset += len(page)
+		it.done = len(page) < it.pageSize
+	}
+	it.value = it.page[it.next]
+	it.next++
+	return true
+}
+
+// Value returns the result Next advanced to.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -1,67 +1,8 @@
+// Command api serves books and authors together from one database.
 package main
 
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/joho/godotenv"
-)
+import "geniuscrew/internal/app"
 
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
-	log.Println("Starting server...")
-
-	// initialize data sources
-	ds, err := initDS()
-
-	if err != nil {
-		log.Fatalf("Unable to initialize data sources: %v\n", err)
-	}
-
-	router := inject(ds)
-
-	if err != nil {
-		log.Fatalf("Failure to inject data sources: %v\n", err)
-	}
-	srv := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", os.Getenv("APP_BASE_URL"), os.Getenv("APP_PORT")),
-		Handler: router,
-	}
-
-	// Graceful server shutdown
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to initialize server: %v\n", err)
-		}
-	}()
-
-	log.Printf("Listening on port %v\n", srv.Addr)
-
-	// Wait for kill signal of channel
-	quit := make(chan os.Signal, 1)
-
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// This blocks until a signal is passed into the quit channel
-	<-quit
-
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Shutdown server
-	log.Println("Shutting down server...")
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v\n", err)
-	}
+	app.Run(app.Options{Name: "geniuscrew", Books: true, Authors: true})
 }
@@ -0,0 +1,9 @@
+// Command authors serves the author service on its own, resolving books
+// through the book service at BOOKS_SERVICE_URL.
+package main
+
+import "geniuscrew/internal/app"
+
+func main() {
+	app.Run(app.Options{Name: "geniuscrew-authors", Authors: true, RemoteBooks: true})
+}
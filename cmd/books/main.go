@@ -0,0 +1,8 @@
+// Command books serves the book service on its own.
+package main
+
+import "geniuscrew/internal/app"
+
+func main() {
+	app.Run(app.Options{Name: "geniuscrew-books", Books: true})
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/internal/helpers"
+)
+
+func authorsCreate(ctx context.Context, name string, args []string) error {
+	var g globals
+	var req v1.CreateAuthorRequest
+	var books string
+	flags := newFlagSet(name, &g)
+	flags.StringVar(&req.Name, "name", "", "")
+	flags.StringVar(&req.Surname, "surname", "", "")
+	flags.StringVar(&req.Email, "email", "", "")
+	flags.StringVar(&books, "books", "", "")
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	req.BooksPublished = splitList(books)
+	return withSession(ctx, g, func(s *session) error {
+		author, err := s.catalog.CreateAuthor(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.out.authors(author)
+	})
+}
+
+func authorsGet(ctx context.Context, name string, args []string) error {
+	var g globals
+	id, err := idArg(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		author, err := s.catalog.GetAuthor(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.out.authors(author)
+	})
+}
+
+func authorsSearch(ctx context.Context, name string, args []string) error {
+	var g globals
+	var q search
+	if err := q.parse(newFlagSet(name, &g), args, authorSearchFields); err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		authors, err := s.catalog.SearchAuthors(ctx, q.field, q.value, q.limit, q.offset)
+		if err != nil {
+			return err
+		}
+		return s.out.authors(authors...)
+	})
+}
+
+// authorsUpdate changes the given fields of an author. Unlike the API, it
+// keeps the linked books unless -books is given.
+func authorsUpdate(ctx context.Context, name string, args []string) error {
+	var g globals
+	var update v1.UpdateAuthorRequest
+	var books string
+	flags := newFlagSet(name, &g)
+	flags.StringVar(&update.Name, "name", "", "")
+	flags.StringVar(&update.Surname, "surname", "", "")
+	flags.StringVar(&update.Email, "email", "", "")
+	flags.StringVar(&books, "books", "", "")
+	id, err := idArg(flags, args)
+	if err != nil {
+		return err
+	}
+	setBooks := false
+	flags.Visit(func(f *flag.Flag) { setBooks = setBooks || f.Name == "books" })
+	if !setBooks && update.Name == "" && update.Surname == "" && update.Email == "" {
+		return fmt.Errorf("%w: nothing to update", errUsage)
+	}
+	return withSession(ctx, g, func(s *session) error {
+		author, err := s.catalog.GetAuthor(ctx, id)
+		if err != nil {
+			return err
+		}
+		update.BooksPublished = linkedISBNs(author)
+		if setBooks {
+			update.BooksPublished = splitList(books)
+		}
+		if err := s.catalog.UpdateAuthor(ctx, id, update); err != nil {
+			return err
+		}
+		return printAuthor(ctx, s, id)
+	})
+}
+
+func authorsDelete(ctx context.Context, name string, args []string) error {
+	var g globals
+	id, err := idArg(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		if err := s.catalog.DeleteAuthor(ctx, id); err != nil {
+			return err
+		}
+		return s.out.message(fmt.Sprintf("author %d deleted", id))
+	})
+}
+
+func authorsLink(ctx context.Context, name string, args []string) error {
+	return relink(ctx, name, args, func(linked []string, isbns []string) []string {
+		for _, isbn := range isbns {
+			if !helpers.In(isbn, linked...) {
+				linked = append(linked, isbn)
+			}
+		}
+		return linked
+	})
+}
+
+func authorsUnlink(ctx context.Context, name string, args []string) error {
+	return relink(ctx, name, args, func(linked []string, isbns []string) []string {
+		kept := linked[:0]
+		for _, isbn := range linked {
+			if !helpers.In(isbn, isbns...) {
+				kept = append(kept, isbn)
+			}
+		}
+		return kept
+	})
+}
+
+// relink replaces the books of the author whose id is the first of args with
+// what change makes of their ISBNs and the ISBNs in the rest of args.
+func relink(ctx context.Context, name string, args []string, change func(linked, isbns []string) []string) error {
+	var g globals
+	positional, err := parse(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("%w: expected an author id and ISBNs", errUsage)
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		author, err := s.catalog.GetAuthor(ctx, id)
+		if err != nil {
+			return err
+		}
+		isbns := change(linkedISBNs(author), positional[1:])
+		if err := s.catalog.UpdateAuthor(ctx, id, v1.UpdateAuthorRequest{BooksPublished: isbns}); err != nil {
+			return err
+		}
+		return printAuthor(ctx, s, id)
+	})
+}
+
+func printAuthor(ctx context.Context, s *session, id int) error {
+	author, err := s.catalog.GetAuthor(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.out.authors(author)
+}
+
+func linkedISBNs(author v1.Author) []string {
+	isbns := make([]string, 0, len(author.BooksPublished))
+	for _, book := range author.BooksPublished {
+		isbns = append(isbns, book.ISBN)
+	}
+	return isbns
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	v1 "geniuscrew/api/v1"
+)
+
+func booksCreate(ctx context.Context, name string, args []string) error {
+	var g globals
+	var req v1.CreateBookRequest
+	flags := newFlagSet(name, &g)
+	flags.StringVar(&req.Title, "title", "", "")
+	flags.StringVar(&req.Description, "description", "", "")
+	flags.StringVar(&req.ISBN, "isbn", "", "")
+	flags.StringVar(&req.PublishingCompany, "publisher", "", "")
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		book, err := s.catalog.CreateBook(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.out.books(book)
+	})
+}
+
+func booksGet(ctx context.Context, name string, args []string) error {
+	var g globals
+	id, err := idArg(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		book, err := s.catalog.GetBook(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.out.books(book)
+	})
+}
+
+func booksSearch(ctx context.Context, name string, args []string) error {
+	var g globals
+	var q search
+	if err := q.parse(newFlagSet(name, &g), args, bookSearchFields); err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		books, err := s.catalog.SearchBooks(ctx, q.field, q.value, q.limit, q.offset)
+		if err != nil {
+			return err
+		}
+		return s.out.books(books...)
+	})
+}
+
+func booksUpdate(ctx context.Context, name string, args []string) error {
+	var g globals
+	var update v1.UpdateBookRequest
+	flags := newFlagSet(name, &g)
+	flags.StringVar(&update.Title, "title", "", "")
+	flags.StringVar(&update.Description, "description", "", "")
+	flags.StringVar(&update.ISBN, "isbn", "", "")
+	flags.StringVar(&update.PublishingCompany, "publisher", "", "")
+	id, err := idArg(flags, args)
+	if err != nil {
+		return err
+	}
+	if update == (v1.UpdateBookRequest{}) {
+		return fmt.Errorf("%w: nothing to update", errUsage)
+	}
+	return withSession(ctx, g, func(s *session) error {
+		book, err := s.catalog.UpdateBook(ctx, id, update)
+		if err != nil {
+			return err
+		}
+		return s.out.books(book)
+	})
+}
+
+func booksDelete(ctx context.Context, name string, args []string) error {
+	var g globals
+	id, err := idArg(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		if err := s.catalog.DeleteBook(ctx, id); err != nil {
+			return err
+		}
+		return s.out.message(fmt.Sprintf("book %d deleted", id))
+	})
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+	"time"
+
+	_mysqlAuthorRepo "geniuscrew/author/repository/mysql"
+	_mysqlBookRepo "geniuscrew/book/repository/mysql"
+	_mysqlOutboxRepo "geniuscrew/outbox/repository/mysql"
+
+	_authorService "geniuscrew/author/service"
+	_bookService "geniuscrew/book/service"
+	_outboxService "geniuscrew/outbox/service"
+
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/client"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/txn"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// catalog is what the commands manage, through the API or the database.
+// Searches return no results, not an error, when nothing matches; a limit of
+// zero returns every match.
+type catalog interface {
+	CreateBook(ctx context.Context, book v1.CreateBookRequest) (v1.Book, error)
+	GetBook(ctx context.Context, id int) (v1.Book, error)
+	SearchBooks(ctx context.Context, field, value string, limit, offset int) ([]v1.Book, error)
+	UpdateBook(ctx context.Context, id int, update v1.UpdateBookRequest) (v1.Book, error)
+	DeleteBook(ctx context.Context, id int) error
+
+	CreateAuthor(ctx context.Context, author v1.CreateAuthorRequest) (v1.Author, error)
+	GetAuthor(ctx context.Context, id int) (v1.Author, error)
+	SearchAuthors(ctx context.Context, field, value string, limit, offset int) ([]v1.Author, error)
+	// UpdateAuthor replaces the books of the author with BooksPublished, as
+	// the API does.
+	UpdateAuthor(ctx context.Context, id int, update v1.UpdateAuthorRequest) error
+	DeleteAuthor(ctx context.Context, id int) error
+}
+
+// remote is the catalog behind the HTTP API.
+type remote struct {
+	client *client.Client
+}
+
+func newRemote(p Profile) remote {
+	opts := []client.Option{client.WithTimeout(p.Timeout), client.WithRetries(p.Retries, 200*time.Millisecond)}
+	if p.APIKey != "" {
+		opts = append(opts, client.WithAPIKey(p.APIKey))
+	}
+	if p.Token != "" {
+		opts = append(opts, client.WithBearerToken(p.Token))
+	}
+	return remote{client: client.New(p.URL, opts...)}
+}
+
+func (r remote) CreateBook(ctx context.Context, book v1.CreateBookRequest) (v1.Book, error) {
+	return r.client.CreateBook(ctx, book)
+}
+
+func (r remote) GetBook(ctx context.Context, id int) (v1.Book, error) {
+	return r.client.GetBook(ctx, id)
+}
+
+func (r remote) SearchBooks(ctx context.Context, field, value string, limit, offset int) ([]v1.Book, error) {
+	books, err := r.client.SearchBooks(ctx, client.BookField(field), value, page(limit, offset)...)
+	if errors.Is(err, domain.ErrBookNotFound) {
+		return nil, nil
+	}
+	return books, err
+}
+
+func (r remote) UpdateBook(ctx context.Context, id int, update v1.UpdateBookRequest) (v1.Book, error) {
+	return r.client.UpdateBook(ctx, id, update)
+}
+
+func (r remote) DeleteBook(ctx context.Context, id int) error {
+	return r.client.DeleteBook(ctx, id)
+}
+
+func (r remote) CreateAuthor(ctx context.Context, author v1.CreateAuthorRequest) (v1.Author, error) {
+	return r.client.CreateAuthor(ctx, author)
+}
+
+func (r remote) GetAuthor(ctx context.Context, id int) (v1.Author, error) {
+	return r.client.GetAuthor(ctx, id)
+}
+
+func (r remote) SearchAuthors(ctx context.Context, field, value string, limit, offset int) ([]v1.Author, error) {
+	authors, err := r.client.SearchAuthors(ctx, client.AuthorField(field), value, page(limit, offset)...)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return authors, err
+}
+
+func (r remote) UpdateAuthor(ctx context.Context, id int, update v1.UpdateAuthorRequest) error {
+	return r.client.UpdateAuthor(ctx, id, update)
+}
+
+func (r remote) DeleteAuthor(ctx context.Context, id int) error {
+	return r.client.DeleteAuthor(ctx, id)
+}
+
+func page(limit, offset int) []client.ReadOption {
+	if limit == 0 {
+		return nil
+	}
+	return []client.ReadOption{client.Page(limit, offset)}
+}
+
+// local is the catalog in a database, used through the services without
+// their authorization, for maintenance while the API is down.
+type local struct {
+	books   domain.BookService
+	authors domain.AuthorService
+	mapper  v1.Mapper
+}
+
+// openLocal connects to the database at dsn. Changes are logged as made by
+// catalogctl on behalf of the operating system user.
+func openLocal(ctx context.Context, dsn string) (catalog, func() error, error) {
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	bookRepo := _mysqlBookRepo.NewMySqlBookRepository(db)
+	// changes are recorded in the outbox, for the servers to dispatch
+	transactor := txn.NewTransactor(db)
+	publisher := _outboxService.NewOutboxPublisher(_mysqlOutboxRepo.NewMySqlOutboxRepository(db), transactor, func() {})
+	mapper := v1.Mapper{Depth: 1}
+	return &local{
+		books: _bookService.NewPublishingBookService(_bookService.NewBookService(bookRepo), transactor, publisher, mapper),
+		authors: _authorService.NewPublishingAuthorService(_authorService.NewAuthorService(
+			_mysqlAuthorRepo.NewMySqlAuthorRepository(db),
+			_mysqlAuthorRepo.NewMySqlAuthorBooksRepository(db),
+			bookRepo,
+		), transactor, publisher, mapper),
+		mapper: mapper,
+	}, sqlDB.Close, nil
+}
+
+// actor marks ctx with the caller for the audit logs of the services.
+func actor(ctx context.Context) context.Context {
+	subject := "catalogctl"
+	if u, err := user.Current(); err == nil {
+		subject += "/" + u.Username
+	}
+	return auth.WithPrincipal(ctx, auth.Principal{Subject: subject})
+}
+
+// validate checks input the way the API does.
+func validate(input interface{}) error {
+	if failed := appvalidator.InputValidator(input); failed != nil {
+		return fmt.Errorf("invalid fields %v", failed)
+	}
+	return nil
+}
+
+func (l *local) CreateBook(ctx context.Context, req v1.CreateBookRequest) (v1.Book, error) {
+	if err := validate(req); err != nil {
+		return v1.Book{}, err
+	}
+	book := req.Book()
+	if err := l.books.Create(actor(ctx), &book); err != nil {
+		return v1.Book{}, err
+	}
+	return l.mapper.Book(book), nil
+}
+
+func (l *local) GetBook(ctx context.Context, id int) (v1.Book, error) {
+	book, err := l.books.Get(ctx, strconv.Itoa(id), domain.QueryOptions{})
+	return l.mapper.Book(book), err
+}
+
+func (l *local) SearchBooks(ctx context.Context, field, value string, limit, offset int) ([]v1.Book, error) {
+	books, err := l.books.GetByFilter(ctx, field, value, domain.QueryOptions{Limit: limit, Offset: offset})
+	if errors.Is(err, domain.ErrBookNotFound) {
+		return nil, nil
+	}
+	return l.mapper.Books(books), err
+}
+
+func (l *local) UpdateBook(ctx context.Context, id int, update v1.UpdateBookRequest) (v1.Book, error) {
+	if err := validate(update); err != nil {
+		return v1.Book{}, err
+	}
+	book, err := l.books.Get(ctx, strconv.Itoa(id), domain.QueryOptions{})
+	if err != nil {
+		return v1.Book{}, err
+	}
+	err = l.books.Update(actor(ctx), strconv.Itoa(id), &book, update.Book())
+	return l.mapper.Book(book), err
+}
+
+func (l *local) DeleteBook(ctx context.Context, id int) error {
+	var book domain.Book
+	return l.books.Delete(actor(ctx), strconv.Itoa(id), &book)
+}
+
+func (l *local) CreateAuthor(ctx context.Context, req v1.CreateAuthorRequest) (v1.Author, error) {
+	if err := validate(req); err != nil {
+		return v1.Author{}, err
+	}
+	author := req.Author()
+	if err := l.authors.Create(actor(ctx), req.BooksPublished, &author); err != nil {
+		return v1.Author{}, err
+	}
+	return l.mapper.Author(author), nil
+}
+
+func (l *local) GetAuthor(ctx context.Context, id int) (v1.Author, error) {
+	author, err := l.authors.Get(ctx, strconv.Itoa(id), domain.QueryOptions{})
+	return l.mapper.Author(author), err
+}
+
+func (l *local) SearchAuthors(ctx context.Context, field, value string, limit, offset int) ([]v1.Author, error) {
+	authors, err := l.authors.GetByFilter(ctx, field, value, domain.QueryOptions{Limit: limit, Offset: offset})
+	return l.mapper.Authors(authors), err
+}
+
+func (l *local) UpdateAuthor(ctx context.Context, id int, update v1.UpdateAuthorRequest) error {
+	if err := validate(update); err != nil {
+		return err
+	}
+	author, err := l.authors.Get(ctx, strconv.Itoa(id), domain.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	return l.authors.Update(actor(ctx), strconv.Itoa(id), &author, update.Author(), update.BooksPublished)
+}
+
+func (l *local) DeleteAuthor(ctx context.Context, id int) error {
+	var author domain.Author
+	return l.authors.Delete(actor(ctx), strconv.Itoa(id), &author)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "geniuscrew/api/v1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setup writes a profile file for url and captures the output of commands.
+func setup(t *testing.T, url string) (string, *bytes.Buffer) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "current: test\nprofiles:\n  test:\n    url: " + url + "\n    api_key: ${CATALOGCTL_TEST_KEY}\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CATALOGCTL_TEST_KEY", "gck_test_secret")
+	var out bytes.Buffer
+	stdout = &out
+	t.Cleanup(func() { stdout = os.Stdout })
+	return path, &out
+}
+
+func TestLoadProfile(t *testing.T) {
+	as := assert.New(t)
+	path, _ := setup(t, "http://catalog.test")
+
+	profile, err := loadProfile(path, "")
+	as.NoError(err)
+	as.Equal("http://catalog.test", profile.URL)
+	as.Equal("gck_test_secret", profile.APIKey)
+	as.Equal(defaultProfile.Timeout, profile.Timeout)
+
+	_, err = loadProfile(path, "prod")
+	as.ErrorContains(err, `no profile "prod"`)
+
+	_, err = loadProfile(filepath.Join(t.TempDir(), "missing.yaml"), "")
+	as.Error(err)
+}
+
+func TestBooksGet(t *testing.T) {
+	as := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		as.Equal("/api/v1/books/4", r.URL.Path)
+		as.Equal("gck_test_secret", r.Header.Get("X-API-Key"))
+		w.WriteHeader(http.StatusFound)
+		w.Write([]byte(`{"payload":{"id":4,"title":"Dune","ISBN":"978160309028","authors":[{"id":1,"name":"Frank","surname":"Herbert"}]}}`))
+	}))
+	defer server.Close()
+	config, out := setup(t, server.URL)
+
+	as.NoError(run(context.Background(), []string{"books", "get", "4", "-config", config}))
+	as.Contains(out.String(), "Frank Herbert")
+
+	out.Reset()
+	as.NoError(run(context.Background(), []string{"books", "get", "-config", config, "-o", "yaml", "4"}))
+	as.Contains(out.String(), "- id: 4\n  title: Dune\n  description: \"\"\n  ISBN: \"978160309028\"\n")
+}
+
+func TestAuthorsLink(t *testing.T) {
+	as := assert.New(t)
+	var linked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var update v1.UpdateAuthorRequest
+			as.NoError(json.NewDecoder(r.Body).Decode(&update))
+			linked = update.BooksPublished
+			return
+		}
+		w.WriteHeader(http.StatusFound)
+		w.Write([]byte(`{"payload":{"id":7,"email":"frank@example.com","books_published":[{"id":1,"ISBN":"978160309028"}]}}`))
+	}))
+	defer server.Close()
+	config, _ := setup(t, server.URL)
+
+	as.NoError(run(context.Background(), []string{"authors", "link", "-config", config, "7", "978160309029"}))
+	as.Equal([]string{"978160309028", "978160309029"}, linked)
+
+	// an update without -books keeps the linked books
+	as.NoError(run(context.Background(), []string{"authors", "update", "7", "-name", "Frank", "-config", config}))
+	as.Equal([]string{"978160309028"}, linked)
+}
+
+func TestUsage(t *testing.T) {
+	as := assert.New(t)
+	for _, args := range [][]string{
+		{},
+		{"books"},
+		{"shelves", "get"},
+		{"books", "get"},
+		{"books", "get", "four"},
+		{"books", "update", "4"},
+		{"books", "search", "-field", "isbn", "-value", "978"},
+		{"authors", "search", "-field", "name", "-offset", "10"},
+		{"books", "get", "4", "-o", "xml"},
+	} {
+		err := run(context.Background(), args)
+		as.True(errors.Is(err, errUsage), "%v: %v", args, err)
+	}
+}
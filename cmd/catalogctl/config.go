@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"geniuscrew/domain"
+	"geniuscrew/internal/helpers"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is one catalog deployment catalogctl can manage. api_key, token
+// and dsn may reference environment variables, e.g. ${CATALOG_API_KEY}, so
+// that secrets stay out of the file.
+type Profile struct {
+	// URL is the base URL of the HTTP API.
+	URL     string        `yaml:"url"`
+	APIKey  string        `yaml:"api_key"`
+	Token   string        `yaml:"token"`
+	Timeout time.Duration `yaml:"timeout"`
+	Retries int           `yaml:"retries"`
+	// Output is the default output format.
+	Output string `yaml:"output"`
+	// DSN is the MySQL data source name used by -offline.
+	DSN string `yaml:"dsn"`
+}
+
+// profileFile is the layout of the profile file:
+//
+//	current: prod
+//	profiles:
+//	  prod:
+//	    url: https://catalog.example.com
+//	    api_key: ${CATALOG_API_KEY}
+//	    output: table
+type profileFile struct {
+	Current  string             `yaml:"current"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// defaultProfile is used when there is no profile file: the API as
+// cmd/api/.env.example runs it.
+var defaultProfile = Profile{URL: "http://localhost:8080", Timeout: 10 * time.Second, Retries: 2}
+
+// loadProfile reads the profile named name, or the current one when name is
+// empty, from the file at path. A missing file is only an error when path
+// was given explicitly.
+func loadProfile(path, name string) (Profile, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("CATALOGCTL_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return defaultProfile, nil
+		}
+		path = filepath.Join(dir, "catalogctl", "config.yaml")
+	}
+	if name == "" {
+		name = os.Getenv("CATALOGCTL_PROFILE")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit && name == "" {
+		return defaultProfile, nil
+	}
+	if err != nil {
+		return Profile{}, err
+	}
+	var file profileFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Profile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if name == "" {
+		name = file.Current
+	}
+	if name == "" {
+		name = "default"
+	}
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%s: no profile %q", path, name)
+	}
+	profile.APIKey = os.ExpandEnv(profile.APIKey)
+	profile.Token = os.ExpandEnv(profile.Token)
+	profile.DSN = os.ExpandEnv(profile.DSN)
+	if profile.URL == "" {
+		profile.URL = defaultProfile.URL
+	}
+	if profile.Timeout == 0 {
+		profile.Timeout = defaultProfile.Timeout
+	}
+	return profile, nil
+}
+
+// the fields the search endpoints accept
+var (
+	bookSearchFields   = []string{"title", "description"}
+	authorSearchFields = []string{"name", "surname", "email"}
+)
+
+// globals are the flags every command accepts.
+type globals struct {
+	config  string
+	profile string
+	output  string
+	offline bool
+}
+
+// newFlagSet returns the flag set of command name with the global flags
+// bound to g.
+func newFlagSet(name string, g *globals) *flag.FlagSet {
+	flags := flag.NewFlagSet("catalogctl "+name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&g.config, "config", "", "profile `file`")
+	flags.StringVar(&g.profile, "profile", "", "profile `name`")
+	flags.StringVar(&g.output, "o", "", "output `format`: table, json or yaml")
+	flags.BoolVar(&g.offline, "offline", false, "use the database instead of the API")
+	return flags
+}
+
+// parse parses args into flags, allowing flags after positional arguments,
+// and returns the positional arguments.
+func parse(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// stdout is where results are written.
+var stdout io.Writer = os.Stdout
+
+// session is what a command works with once its flags are parsed.
+type session struct {
+	catalog catalog
+	out     printer
+	close   func() error
+}
+
+// open connects to the catalog of the selected profile.
+func (g globals) open(ctx context.Context) (*session, error) {
+	profile, err := loadProfile(g.config, g.profile)
+	if err != nil {
+		return nil, err
+	}
+	format := g.output
+	if format == "" {
+		format = profile.Output
+	}
+	out, err := newPrinter(stdout, format)
+	if err != nil {
+		return nil, err
+	}
+	if !g.offline {
+		return &session{catalog: newRemote(profile), out: out, close: func() error { return nil }}, nil
+	}
+	if profile.DSN == "" {
+		return nil, errors.New("-offline needs a profile with a dsn")
+	}
+	cat, closeDB, err := openLocal(ctx, profile.DSN)
+	if err != nil {
+		return nil, err
+	}
+	return &session{catalog: cat, out: out, close: closeDB}, nil
+}
+
+// withSession opens the catalog g selects for the duration of fn.
+func withSession(ctx context.Context, g globals, fn func(s *session) error) error {
+	s, err := g.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.close()
+	return fn(s)
+}
+
+// noArgs parses args, which must only hold flags.
+func noArgs(flags *flag.FlagSet, args []string) error {
+	positional, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+	}
+	return nil
+}
+
+// idArg parses args, which must hold one id besides flags.
+func idArg(flags *flag.FlagSet, args []string) (int, error) {
+	positional, err := parse(flags, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(positional) != 1 {
+		return 0, fmt.Errorf("%w: expected one id", errUsage)
+	}
+	return parseID(positional[0])
+}
+
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%w: invalid id %q", errUsage, arg)
+	}
+	return id, nil
+}
+
+// search is the query of a search command.
+type search struct {
+	field, value  string
+	limit, offset int
+}
+
+func (q *search) parse(flags *flag.FlagSet, args []string, fields []string) error {
+	flags.StringVar(&q.field, "field", "", "")
+	flags.StringVar(&q.value, "value", "", "")
+	flags.IntVar(&q.limit, "limit", 0, "")
+	flags.IntVar(&q.offset, "offset", 0, "")
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	if !helpers.In(q.field, fields...) {
+		return fmt.Errorf("%w: -field must be one of %s", errUsage, strings.Join(fields, ", "))
+	}
+	if q.limit < 0 || q.limit > domain.MaxLimit {
+		return fmt.Errorf("%w: -limit must be between 1 and %d", errUsage, domain.MaxLimit)
+	}
+	if q.offset < 0 || q.offset > 0 && q.limit == 0 {
+		return fmt.Errorf("%w: -offset must not be negative and needs -limit", errUsage)
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,107 @@
+// Command catalogctl manages the catalog from the command line. It creates,
+// reads, updates, deletes and searches books and authors, links authors to
+// books, imports and exports the catalog and shows statistics, either through
+// the HTTP API of a profile or, with -offline, straight against the profile's
+// database.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `usage: catalogctl <command> [flags] [args]
+
+commands:
+  books create -title T -description D -isbn I -publisher P
+  books get ID
+  books search -field title|description -value V [-limit N [-offset N]]
+  books update ID [-title T] [-description D] [-isbn I] [-publisher P]
+  books delete ID
+  authors create -name N -surname S -email E -books ISBN[,ISBN...]
+  authors get ID
+  authors search -field name|surname|email -value V [-limit N [-offset N]]
+  authors update ID [-name N] [-surname S] [-email E] [-books ISBN[,ISBN...]]
+  authors delete ID
+  authors link ID ISBN...
+  authors unlink ID ISBN...
+  import FILE
+  export [-format json|yaml] [FILE]
+  stats
+
+flags of every command:
+  -config FILE   profile file, by default $CATALOGCTL_CONFIG or
+                 ~/.config/catalogctl/config.yaml
+  -profile NAME  profile to use, by default $CATALOGCTL_PROFILE or the
+                 current profile of the file
+  -o FORMAT      output as table, json or yaml
+  -offline       use the database of the profile instead of the API
+`
+
+// errUsage reports a command line that does not make sense; usage is printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(os.Stderr, usage)
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "catalogctl: %v\n\n%s", err, usage)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "catalogctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches args to the command they name.
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: no command", errUsage)
+	}
+	name, args := args[0], args[1:]
+	switch name {
+	case "books", "authors":
+		if len(args) == 0 {
+			return fmt.Errorf("%w: %s needs a subcommand", errUsage, name)
+		}
+		name += " " + args[0]
+		args = args[1:]
+	case "help", "-h", "-help", "--help":
+		return flag.ErrHelp
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, name)
+	}
+	return cmd(ctx, name, args)
+}
+
+// commands maps command names to their implementation.
+var commands = map[string]func(ctx context.Context, name string, args []string) error{
+	"books create":   booksCreate,
+	"books get":      booksGet,
+	"books search":   booksSearch,
+	"books update":   booksUpdate,
+	"books delete":   booksDelete,
+	"authors create": authorsCreate,
+	"authors get":    authorsGet,
+	"authors search": authorsSearch,
+	"authors update": authorsUpdate,
+	"authors delete": authorsDelete,
+	"authors link":   authorsLink,
+	"authors unlink": authorsUnlink,
+	"import":         importCatalog,
+	"export":         exportCatalog,
+	"stats":          stats,
+}
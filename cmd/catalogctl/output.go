@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	v1 "geniuscrew/api/v1"
+
+	"gopkg.in/yaml.v3"
+)
+
+// printer writes command results in one output format.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (printer, error) {
+	switch format {
+	case "":
+		format = "table"
+	case "table", "json", "yaml":
+	default:
+		return printer{}, fmt.Errorf("%w: unknown output format %q", errUsage, format)
+	}
+	return printer{w: w, format: format}, nil
+}
+
+// print writes v as JSON or YAML, or calls table with a tab separated writer
+// for the table format.
+func (p printer) print(v interface{}, table func(w io.Writer)) error {
+	switch p.format {
+	case "json":
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		return writeYAML(p.w, v)
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	}
+}
+
+// writeYAML writes v as YAML with the field names and order of its JSON
+// encoding.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML in flow style; decoding it into a node keeps the order of
+	// the keys, and clearing the styles turns it into block style
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func (p printer) books(books ...v1.Book) error {
+	return p.print(books, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTITLE\tISBN\tPUBLISHER\tAUTHORS")
+		for _, b := range books {
+			authors := make([]string, 0, len(b.Authors))
+			for _, a := range b.Authors {
+				authors = append(authors, strings.TrimSpace(a.Name+" "+a.Surname))
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", b.ID, b.Title, b.ISBN, b.PublishingCompany, strings.Join(authors, ", "))
+		}
+	})
+}
+
+func (p printer) authors(authors ...v1.Author) error {
+	return p.print(authors, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tSURNAME\tEMAIL\tBOOKS")
+		for _, a := range authors {
+			isbns := make([]string, 0, len(a.BooksPublished))
+			for _, b := range a.BooksPublished {
+				isbns = append(isbns, b.ISBN)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", a.ID, a.Name, a.Surname, a.Email, strings.Join(isbns, ", "))
+		}
+	})
+}
+
+// message reports the outcome of a command without a result of its own.
+func (p printer) message(text string) error {
+	return p.print(map[string]string{"message": text}, func(w io.Writer) {
+		fmt.Fprintln(w, text)
+	})
+}
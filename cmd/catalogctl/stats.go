@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// catalogStats are counts over the whole catalog.
+type catalogStats struct {
+	Books              int `json:"books"`
+	Authors            int `json:"authors"`
+	Links              int `json:"links"`
+	BooksWithoutAuthor int `json:"books_without_author"`
+	AuthorsWithoutBook int `json:"authors_without_book"`
+}
+
+func stats(ctx context.Context, name string, args []string) error {
+	var g globals
+	if err := noArgs(newFlagSet(name, &g), args); err != nil {
+		return err
+	}
+	return withSession(ctx, g, func(s *session) error {
+		books, err := all(ctx, s.catalog.SearchBooks, "title")
+		if err != nil {
+			return err
+		}
+		authors, err := all(ctx, s.catalog.SearchAuthors, "name")
+		if err != nil {
+			return err
+		}
+		st := catalogStats{Books: len(books), Authors: len(authors)}
+		for _, b := range books {
+			if len(b.Authors) == 0 {
+				st.BooksWithoutAuthor++
+			}
+		}
+		for _, a := range authors {
+			st.Links += len(a.BooksPublished)
+			if len(a.BooksPublished) == 0 {
+				st.AuthorsWithoutBook++
+			}
+		}
+		return s.out.print(st, func(w io.Writer) {
+			fmt.Fprintf(w, "books\t%d\n", st.Books)
+			fmt.Fprintf(w, "authors\t%d\n", st.Authors)
+			fmt.Fprintf(w, "links\t%d\n", st.Links)
+			fmt.Fprintf(w, "books without author\t%d\n", st.BooksWithoutAuthor)
+			fmt.Fprintf(w, "authors without book\t%d\n", st.AuthorsWithoutBook)
+		})
+	})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dump is the file format of import and export, in JSON or YAML:
+// the books, then the authors with the ISBNs of their books.
+type dump struct {
+	Books   []v1.CreateBookRequest   `json:"books"`
+	Authors []v1.CreateAuthorRequest `json:"authors"`
+}
+
+// importResult counts what an import did with each entry.
+type importResult struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+	Failed   int `json:"failed"`
+}
+
+// importCatalog creates the books and then the authors of a dump, skipping
+// those that already exist. Entries that fail are reported and the import
+// goes on; the command fails if any did.
+func importCatalog(ctx context.Context, name string, args []string) error {
+	var g globals
+	positional, err := parse(newFlagSet(name, &g), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%w: expected one file", errUsage)
+	}
+	data, err := os.ReadFile(positional[0])
+	if err != nil {
+		return err
+	}
+	d, err := decodeDump(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", positional[0], err)
+	}
+	return withSession(ctx, g, func(s *session) error {
+		var books, authors importResult
+		for _, book := range d.Books {
+			_, err := s.catalog.CreateBook(ctx, book)
+			count(&books, err, "book "+book.ISBN)
+		}
+		for _, author := range d.Authors {
+			_, err := s.catalog.CreateAuthor(ctx, author)
+			count(&authors, err, "author "+author.Email)
+		}
+		result := map[string]importResult{"books": books, "authors": authors}
+		err := s.out.print(result, func(w io.Writer) {
+			fmt.Fprintln(w, "\tCREATED\tEXISTING\tFAILED")
+			fmt.Fprintf(w, "books\t%d\t%d\t%d\n", books.Created, books.Existing, books.Failed)
+			fmt.Fprintf(w, "authors\t%d\t%d\t%d\n", authors.Created, authors.Existing, authors.Failed)
+		})
+		if err == nil && books.Failed+authors.Failed > 0 {
+			err = fmt.Errorf("%d entries failed", books.Failed+authors.Failed)
+		}
+		return err
+	})
+}
+
+func count(r *importResult, err error, entry string) {
+	switch {
+	case err == nil:
+		r.Created++
+	case errors.Is(err, domain.ErrDuplicateRecord):
+		r.Existing++
+	default:
+		r.Failed++
+		fmt.Fprintf(os.Stderr, "catalogctl: %s: %v\n", entry, err)
+	}
+}
+
+// decodeDump reads a dump in YAML or JSON, which is YAML too. The YAML is
+// converted to JSON first so that both use the JSON field names.
+func decodeDump(data []byte) (dump, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return dump{}, err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return dump{}, err
+	}
+	var d dump
+	err = json.Unmarshal(data, &d)
+	return d, err
+}
+
+// exportCatalog writes every book and author as a dump, to a file or to
+// standard output. Authors without books are exported but cannot be imported
+// again, as authors are created with at least one book.
+func exportCatalog(ctx context.Context, name string, args []string) error {
+	var g globals
+	var format string
+	flags := newFlagSet(name, &g)
+	flags.StringVar(&format, "format", "", "")
+	positional, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return fmt.Errorf("%w: expected at most one file", errUsage)
+	}
+	path := ""
+	if len(positional) == 1 {
+		path = positional[0]
+	}
+	if format == "" {
+		format = "json"
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+	}
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("%w: -format must be json or yaml", errUsage)
+	}
+
+	return withSession(ctx, g, func(s *session) error {
+		books, err := all(ctx, s.catalog.SearchBooks, "title")
+		if err != nil {
+			return err
+		}
+		authors, err := all(ctx, s.catalog.SearchAuthors, "name")
+		if err != nil {
+			return err
+		}
+		d := dump{
+			Books:   make([]v1.CreateBookRequest, 0, len(books)),
+			Authors: make([]v1.CreateAuthorRequest, 0, len(authors)),
+		}
+		for _, b := range books {
+			d.Books = append(d.Books, v1.CreateBookRequest{
+				Title:             b.Title,
+				Description:       b.Description,
+				ISBN:              b.ISBN,
+				PublishingCompany: b.PublishingCompany,
+			})
+		}
+		for _, a := range authors {
+			d.Authors = append(d.Authors, v1.CreateAuthorRequest{
+				Name:           a.Name,
+				Surname:        a.Surname,
+				Email:          a.Email,
+				BooksPublished: linkedISBNs(a),
+			})
+		}
+
+		var buf bytes.Buffer
+		if err := (printer{w: &buf, format: format}).print(d, nil); err != nil {
+			return err
+		}
+		if path == "" {
+			_, err = stdout.Write(buf.Bytes())
+			return err
+		}
+		return os.WriteFile(path, buf.Bytes(), 0o644)
+	})
+}
+
+// all pages through every match of an empty search on field, which matches
+// everything.
+func all[T any](ctx context.Context, search func(ctx context.Context, field, value string, limit, offset int) ([]T, error), field string) ([]T, error) {
+	var results []T
+	for offset := 0; ; offset += domain.MaxLimit {
+		page, err := search(ctx, field, "", domain.MaxLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if len(page) < domain.MaxLimit {
+			return results, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	// ErrAPIKeyInactive is returned when rotating a revoked or expired key.
+	ErrAPIKeyInactive = errors.New("api key is revoked or expired")
+	// ErrUnknownRole is returned when creating a key with a role the
+	// authorization policy does not define.
+	ErrUnknownRole = errors.New("unknown role")
+)
+
+// APIKey is a credential for machine clients. Only a hash of the secret is
+// stored; Prefix identifies the key and is safe to display.
+type APIKey struct {
+	ID         int        `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Prefix     string     `json:"prefix" gorm:"size:32;unique"`
+	Hash       string     `json:"-" gorm:"size:128"`
+	Roles      []string   `json:"roles" gorm:"serializer:json"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// Active reports whether the key may authenticate at time now.
+func (k APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil && !k.RevokedAt.After(now) {
+		return false
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(now) {
+		return false
+	}
+	return true
+}
+
+type APIKeyService interface {
+	// Create stores key with a new secret and returns the secret, which is
+	// not retrievable afterwards.
+	Create(ctx context.Context, key *APIKey) (string, error)
+	List(ctx context.Context) ([]APIKey, error)
+	// Rotate issues a replacement for key id and lets the old key keep
+	// working for the grace period.
+	Rotate(ctx context.Context, id string, grace time.Duration) (APIKey, string, error)
+	SetExpiry(ctx context.Context, id string, expiresAt *time.Time) (APIKey, error)
+	Revoke(ctx context.Context, id string) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	Get(ctx context.Context, id string) (APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+	GetByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	TouchLastUsed(ctx context.Context, id int, usedAt time.Time) error
+}
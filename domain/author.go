@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -10,12 +11,26 @@ var (
 )
 
 type Author struct {
-	ID             int    `json:"id" gorm:"primaryKey"`
-	Name           string `json:"name" validate:"gte=0,lte=500"`
-	Surname        string `json:"surname" validate:"gte=0,lte=500"`
-	Email          string `json:"email" gorm:"unique" validate:"email"`
-	BooksPublished []Book `json:"books_published" gorm:"many2many:author_books;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;" validate:"dive"`
+	ID             int       `json:"id" gorm:"primaryKey"`
+	Name           string    `json:"name" validate:"gte=0,lte=500"`
+	Surname        string    `json:"surname" validate:"gte=0,lte=500"`
+	Email          string    `json:"email" gorm:"unique" validate:"email"`
+	BooksPublished []Book    `json:"books_published" gorm:"many2many:author_books;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;" validate:"dive"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
+
+// LastModified is the latest change to the author or to any of their books.
+func (a Author) LastModified() time.Time {
+	modified := a.UpdatedAt
+	for _, book := range a.BooksPublished {
+		if book.UpdatedAt.After(modified) {
+			modified = book.UpdatedAt
+		}
+	}
+	return modified
+}
+
 type AuthorBooks struct {
 	BookID   int `gorm:"primaryKey" column:"book_id"`
 	AuthorID int `gorm:"primaryKey" column:"author_id"`
@@ -23,8 +38,9 @@ type AuthorBooks struct {
 
 type AuthorService interface {
 	Create(ctx context.Context, books []string, author *Author) error
-	Get(ctx context.Context, id string) (Author, error)
-	GetByFilter(ctx context.Context, filter, filterValue string) ([]Author, error)
+	Get(ctx context.Context, id string, opts QueryOptions) (Author, error)
+	GetByFilter(ctx context.Context, filter, filterValue string, opts QueryOptions) ([]Author, error)
+	GetByIDs(ctx context.Context, ids []int, opts QueryOptions) ([]Author, error)
 	Update(ctx context.Context, id string, author *Author, updatedAuthor Author, booksPublished []string) error
 	Delete(ctx context.Context, id string, author *Author) error
 }
@@ -32,9 +48,10 @@ type AuthorService interface {
 type AuthorRepository interface {
 	Create(ctx context.Context, author *Author) error
 	Update(ctx context.Context, author *Author, updatedAuthor Author) error
-	Get(ctx context.Context, id string) (Author, error)
+	Get(ctx context.Context, id string, opts QueryOptions) (Author, error)
 	Delete(ctx context.Context, id string, author *Author) error
-	GetByFilter(ctx context.Context, filter, filterValue string) ([]Author, error)
+	GetByFilter(ctx context.Context, filter, filterValue string, opts QueryOptions) ([]Author, error)
+	GetByIDs(ctx context.Context, ids []int, opts QueryOptions) ([]Author, error)
 }
 
 type AuthorBooksRepository interface {
@@ -9,6 +9,9 @@ import (
 var (
 	ErrRecordNotFound  = errors.New("record not found")
 	ErrDuplicateRecord = errors.New("duplicate record")
+	// ErrUnavailable is returned when a service the request depends on
+	// cannot be reached.
+	ErrUnavailable = errors.New("service unavailable")
 )
 
 type Book struct {
@@ -23,10 +26,23 @@ type Book struct {
 	UpdatedAt         time.Time `json:"updated_at"`
 }
 
+// LastModified is the latest change to the book or to any of its authors.
+func (b Book) LastModified() time.Time {
+	modified := b.UpdatedAt
+	for _, author := range b.Authors {
+		if author.UpdatedAt.After(modified) {
+			modified = author.UpdatedAt
+		}
+	}
+	return modified
+}
+
 type BookService interface {
 	Create(ctx context.Context, book *Book) error
-	Get(ctx context.Context, id string) (Book, error)
-	GetByFilter(ctx context.Context, filter, filterValue string) ([]Book, error)
+	Get(ctx context.Context, id string, opts QueryOptions) (Book, error)
+	GetByFilter(ctx context.Context, filter, filterValue string, opts QueryOptions) ([]Book, error)
+	GetByIDs(ctx context.Context, ids []int, opts QueryOptions) ([]Book, error)
+	GetByISBNs(ctx context.Context, isbns []string) ([]Book, error)
 	Update(ctx context.Context, id string, book *Book, updatedBook Book) error
 	Delete(ctx context.Context, id string, book *Book) error
 }
@@ -34,8 +50,9 @@ type BookService interface {
 type BookRepository interface {
 	Create(ctx context.Context, book *Book) error
 	Update(ctx context.Context, book *Book, updatedBook Book) error
-	Get(ctx context.Context, id string) (Book, error)
-	GetByFilter(ctx context.Context, filter, filterValue string) ([]Book, error)
+	Get(ctx context.Context, id string, opts QueryOptions) (Book, error)
+	GetByFilter(ctx context.Context, filter, filterValue string, opts QueryOptions) ([]Book, error)
+	GetByIDs(ctx context.Context, ids []int, opts QueryOptions) ([]Book, error)
 	Delete(ctx context.Context, id string, book *Book) error
 	GetByISBN(ctx context.Context, field string, filter []string) ([]Book, error)
 }
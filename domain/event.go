@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChangesExpired is returned when reading changes that are no longer
+// kept.
+var ErrChangesExpired = errors.New("changes are no longer kept")
+
+// Catalog change events, published in the transaction of the change.
+const (
+	EventBookCreated        = "book.created"
+	EventBookUpdated        = "book.updated"
+	EventBookDeleted        = "book.deleted"
+	EventAuthorCreated      = "author.created"
+	EventAuthorUpdated      = "author.updated"
+	EventAuthorDeleted      = "author.deleted"
+	EventAuthorBooksChanged = "author.books_changed"
+)
+
+// EventTypes lists every event webhooks can subscribe to.
+var EventTypes = []string{
+	EventBookCreated, EventBookUpdated, EventBookDeleted,
+	EventAuthorCreated, EventAuthorUpdated, EventAuthorDeleted, EventAuthorBooksChanged,
+}
+
+// Event is a change to the catalog. Data is the API representation of what
+// changed. Events of one Aggregate, e.g. "book/12", are delivered in the
+// order they were published. Seq is the sequence number of a recorded event.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Aggregate  string      `json:"-"`
+	Seq        int         `json:"-"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// NewEvent returns an event of type eventType about aggregate with a new
+// random ID, which consumers use to recognize events delivered twice.
+func NewEvent(eventType, aggregate string, data interface{}) Event {
+	id := make([]byte, 12)
+	rand.Read(id)
+	return Event{ID: "evt_" + hex.EncodeToString(id), Type: eventType, Aggregate: aggregate, OccurredAt: time.Now().UTC(), Data: data}
+}
+
+// EventPublisher hands events to whoever is interested in them.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Transactor runs changes in one transaction. Repositories given the context
+// passed to fn take part in it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit calls fn once the transaction ctx carries is committed,
+	// or now outside of one.
+	AfterCommit(ctx context.Context, fn func())
+}
+
+// OutboxEvent is an event recorded in the transaction of the change it
+// describes, kept until it has been handed to every sink and then for the
+// retention period. Data is the JSON of Event.Data.
+//
+// Seq numbers the events without gaps in the order their transactions
+// committed: a reader that has seen an event has seen every event with a
+// lower Seq. IDs are assigned when events are recorded and may commit out of
+// order.
+type OutboxEvent struct {
+	ID            int    `gorm:"primaryKey"`
+	Seq           int    `gorm:"index"`
+	EventID       string `gorm:"size:32;uniqueIndex"`
+	Type          string `gorm:"size:64"`
+	Aggregate     string `gorm:"size:64;index"`
+	OccurredAt    time.Time
+	Data          json.RawMessage `gorm:"type:mediumtext"`
+	Attempts      int
+	NextAttemptAt time.Time  `gorm:"index"`
+	LastError     string     `gorm:"size:1024"`
+	SentAt        *time.Time `gorm:"index"`
+	CreatedAt     time.Time
+}
+
+// Event returns the event e records.
+func (e OutboxEvent) Event() Event {
+	return Event{ID: e.EventID, Type: e.Type, Aggregate: e.Aggregate, Seq: e.Seq, OccurredAt: e.OccurredAt, Data: e.Data}
+}
+
+type OutboxRepository interface {
+	// Create records event, in the transaction ctx carries if any.
+	Create(ctx context.Context, event *OutboxEvent) error
+	// ClaimDue returns, in order, up to limit unsent events due at now that
+	// are the oldest unsent event of their aggregate, and moves their next
+	// attempt to now+lease so that other instances skip them while they are
+	// being sent.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error)
+	// Update writes the outcome of an attempt.
+	Update(ctx context.Context, event *OutboxEvent) error
+	// DeleteSent removes events sent before t and returns how many.
+	DeleteSent(ctx context.Context, t time.Time) (int64, error)
+	// After returns up to limit events with a Seq greater than seq, in
+	// order, whether sent or not.
+	After(ctx context.Context, seq int, limit int) ([]OutboxEvent, error)
+	// Latest returns the last limit events, in order.
+	Latest(ctx context.Context, limit int) ([]OutboxEvent, error)
+	// FirstSeq returns the lowest Seq still kept, or the next Seq to be
+	// assigned when no event is kept.
+	FirstSeq(ctx context.Context) (int, error)
+	// LastSeq returns the highest Seq assigned, 0 before the first event.
+	LastSeq(ctx context.Context) (int, error)
+}
+
+// EventSequence is the counter the Seq of events is taken from. Taking the
+// next number locks it until the transaction ends, which orders the
+// transactions recording events.
+type EventSequence struct {
+	ID    int `gorm:"primaryKey;autoIncrement:false"`
+	Value int
+}
+
+// Entities events are about.
+const (
+	EntityBook   = "book"
+	EntityAuthor = "author"
+)
+
+// EventFilter selects the events about Entity and, when ID is set, about
+// that book or author only. The zero filter selects every event.
+type EventFilter struct {
+	Entity string
+	ID     int
+}
+
+// Matches reports whether event is selected by f.
+func (f EventFilter) Matches(event Event) bool {
+	switch {
+	case f.Entity == "":
+		return true
+	case f.ID == 0:
+		return strings.HasPrefix(event.Aggregate, f.Entity+"/")
+	default:
+		return event.Aggregate == f.Entity+"/"+strconv.Itoa(f.ID)
+	}
+}
+
+// Subscription follows the events of an EventStream.
+type Subscription struct {
+	// Replay holds the buffered events after the position subscribed from.
+	Replay []Event
+	// Missed is set when some events after that position are no longer
+	// buffered, so the subscriber has to reload what it shows.
+	Missed bool
+	// Events receives the events recorded from then on. It is closed when
+	// the stream stops or the subscriber falls too far behind, after which
+	// it may subscribe again from the last event it got.
+	Events <-chan Event
+}
+
+// ChangeFeed reads the events as a log ordered by Seq.
+type ChangeFeed interface {
+	// Changes returns up to limit events with a Seq greater than since, in
+	// order, and the highest Seq assigned. It returns ErrChangesExpired when
+	// some of those events are no longer kept.
+	Changes(ctx context.Context, since, limit int) ([]Event, int, error)
+}
+
+// EventStream fans the events recorded in the outbox out to subscribers.
+type EventStream interface {
+	// Subscribe follows the events matching filter recorded after the event
+	// with Seq after, or from now when after is 0.
+	Subscribe(ctx context.Context, after int, filter EventFilter) (*Subscription, error)
+	Unsubscribe(sub *Subscription)
+}
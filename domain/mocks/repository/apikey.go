@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type APIKeyRepositoryMock struct {
+	mock.Mock
+}
+
+func (w *APIKeyRepositoryMock) Create(ctx context.Context, key *domain.APIKey) error {
+	output := w.Mock.Called(ctx, key)
+	err := output.Error(0)
+	return err
+}
+
+func (w *APIKeyRepositoryMock) Get(ctx context.Context, id string) (domain.APIKey, error) {
+	output := w.Mock.Called(ctx, id)
+	key := output.Get(0)
+	err := output.Error(1)
+	return key.(domain.APIKey), err
+}
+
+func (w *APIKeyRepositoryMock) List(ctx context.Context) ([]domain.APIKey, error) {
+	output := w.Mock.Called(ctx)
+	keys := output.Get(0)
+	err := output.Error(1)
+	return keys.([]domain.APIKey), err
+}
+
+func (w *APIKeyRepositoryMock) Update(ctx context.Context, key *domain.APIKey) error {
+	output := w.Mock.Called(ctx, key)
+	err := output.Error(0)
+	return err
+}
+
+func (w *APIKeyRepositoryMock) GetByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	output := w.Mock.Called(ctx, prefix)
+	key := output.Get(0)
+	err := output.Error(1)
+	return key.(domain.APIKey), err
+}
+
+func (w *APIKeyRepositoryMock) TouchLastUsed(ctx context.Context, id int, usedAt time.Time) error {
+	output := w.Mock.Called(ctx, id, usedAt)
+	err := output.Error(0)
+	return err
+}
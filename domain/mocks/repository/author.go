@@ -39,15 +39,15 @@ func (w *AuthorRepositoryMock) Create(ctx context.Context, author *domain.Author
 	return err
 }
 
-func (w *AuthorRepositoryMock) Get(ctx context.Context, id string) (domain.Author, error) {
-	output := w.Mock.Called(ctx, id)
+func (w *AuthorRepositoryMock) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Author, error) {
+	output := w.Mock.Called(ctx, id, opts)
 	author := output.Get(0)
 	err := output.Error(1)
 	return author.(domain.Author), err
 }
 
-func (w *AuthorRepositoryMock) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Author, error) {
-	output := w.Mock.Called(ctx, filter, filterValue)
+func (w *AuthorRepositoryMock) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Author, error) {
+	output := w.Mock.Called(ctx, filter, filterValue, opts)
 	author := output.Get(0)
 	err := output.Error(1)
 	return author.([]domain.Author), err
@@ -64,3 +64,10 @@ func (w *AuthorRepositoryMock) Delete(ctx context.Context, id string, author *do
 	err := output.Error(0)
 	return err
 }
+
+func (w *AuthorRepositoryMock) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Author, error) {
+	output := w.Mock.Called(ctx, ids, opts)
+	authors := output.Get(0)
+	err := output.Error(1)
+	return authors.([]domain.Author), err
+}
@@ -17,15 +17,15 @@ func (w *BookRepositoryMock) Create(ctx context.Context, book *domain.Book) erro
 	return err
 }
 
-func (w *BookRepositoryMock) Get(ctx context.Context, id string) (domain.Book, error) {
-	output := w.Mock.Called(ctx, id)
+func (w *BookRepositoryMock) Get(ctx context.Context, id string, opts domain.QueryOptions) (domain.Book, error) {
+	output := w.Mock.Called(ctx, id, opts)
 	book := output.Get(0)
 	err := output.Error(1)
 	return book.(domain.Book), err
 }
 
-func (w *BookRepositoryMock) GetByFilter(ctx context.Context, filter, filterValue string) ([]domain.Book, error) {
-	output := w.Mock.Called(ctx, filter, filterValue)
+func (w *BookRepositoryMock) GetByFilter(ctx context.Context, filter, filterValue string, opts domain.QueryOptions) ([]domain.Book, error) {
+	output := w.Mock.Called(ctx, filter, filterValue, opts)
 	book := output.Get(0)
 	err := output.Error(1)
 	return book.([]domain.Book), err
@@ -49,3 +49,10 @@ func (w *BookRepositoryMock) Delete(ctx context.Context, id string, book *domain
 	err := output.Error(0)
 	return err
 }
+
+func (w *BookRepositoryMock) GetByIDs(ctx context.Context, ids []int, opts domain.QueryOptions) ([]domain.Book, error) {
+	output := w.Mock.Called(ctx, ids, opts)
+	books := output.Get(0)
+	err := output.Error(1)
+	return books.([]domain.Book), err
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func (o *OutboxRepositoryMock) Create(ctx context.Context, event *domain.OutboxEvent) error {
+	output := o.Mock.Called(ctx, event)
+	err := output.Error(0)
+	return err
+}
+
+func (o *OutboxRepositoryMock) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	output := o.Mock.Called(ctx, now, lease, limit)
+	events := output.Get(0)
+	err := output.Error(1)
+	return events.([]domain.OutboxEvent), err
+}
+
+func (o *OutboxRepositoryMock) Update(ctx context.Context, event *domain.OutboxEvent) error {
+	output := o.Mock.Called(ctx, event)
+	err := output.Error(0)
+	return err
+}
+
+func (o *OutboxRepositoryMock) DeleteSent(ctx context.Context, t time.Time) (int64, error) {
+	output := o.Mock.Called(ctx, t)
+	deleted := output.Get(0)
+	err := output.Error(1)
+	return deleted.(int64), err
+}
+
+func (o *OutboxRepositoryMock) After(ctx context.Context, seq int, limit int) ([]domain.OutboxEvent, error) {
+	output := o.Mock.Called(ctx, seq, limit)
+	events := output.Get(0)
+	err := output.Error(1)
+	return events.([]domain.OutboxEvent), err
+}
+
+func (o *OutboxRepositoryMock) Latest(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	output := o.Mock.Called(ctx, limit)
+	events := output.Get(0)
+	err := output.Error(1)
+	return events.([]domain.OutboxEvent), err
+}
+
+func (o *OutboxRepositoryMock) FirstSeq(ctx context.Context) (int, error) {
+	output := o.Mock.Called(ctx)
+	seq := output.Get(0)
+	err := output.Error(1)
+	return seq.(int), err
+}
+
+func (o *OutboxRepositoryMock) LastSeq(ctx context.Context) (int, error) {
+	output := o.Mock.Called(ctx)
+	seq := output.Get(0)
+	err := output.Error(1)
+	return seq.(int), err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func (w *WebhookRepositoryMock) Create(ctx context.Context, hook *domain.Webhook) error {
+	output := w.Mock.Called(ctx, hook)
+	err := output.Error(0)
+	return err
+}
+
+func (w *WebhookRepositoryMock) Get(ctx context.Context, id string) (domain.Webhook, error) {
+	output := w.Mock.Called(ctx, id)
+	hook := output.Get(0)
+	err := output.Error(1)
+	return hook.(domain.Webhook), err
+}
+
+func (w *WebhookRepositoryMock) List(ctx context.Context) ([]domain.Webhook, error) {
+	output := w.Mock.Called(ctx)
+	hooks := output.Get(0)
+	err := output.Error(1)
+	return hooks.([]domain.Webhook), err
+}
+
+func (w *WebhookRepositoryMock) Delete(ctx context.Context, id string) error {
+	output := w.Mock.Called(ctx, id)
+	err := output.Error(0)
+	return err
+}
+
+func (w *WebhookRepositoryMock) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	output := w.Mock.Called(ctx, deliveries)
+	err := output.Error(0)
+	return err
+}
+
+func (w *WebhookRepositoryMock) GetDelivery(ctx context.Context, webhookID, id string) (domain.WebhookDelivery, error) {
+	output := w.Mock.Called(ctx, webhookID, id)
+	delivery := output.Get(0)
+	err := output.Error(1)
+	return delivery.(domain.WebhookDelivery), err
+}
+
+func (w *WebhookRepositoryMock) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	output := w.Mock.Called(ctx, webhookID, limit)
+	deliveries := output.Get(0)
+	err := output.Error(1)
+	return deliveries.([]domain.WebhookDelivery), err
+}
+
+func (w *WebhookRepositoryMock) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	output := w.Mock.Called(ctx, now, lease, limit)
+	deliveries := output.Get(0)
+	err := output.Error(1)
+	return deliveries.([]domain.WebhookDelivery), err
+}
+
+func (w *WebhookRepositoryMock) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	output := w.Mock.Called(ctx, delivery)
+	err := output.Error(0)
+	return err
+}
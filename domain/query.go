@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrInvalidPage  = errors.New("invalid page")
+)
+
+// MaxLimit is the largest page a search returns.
+const MaxLimit = 100
+
+// BookColumns maps the API field names of a book to their columns and
+// BookAssociations maps the names of its associations to the struct field
+// holding them.
+var (
+	BookColumns = map[string]string{
+		"id":                 "id",
+		"title":              "title",
+		"description":        "description",
+		"ISBN":               "isbn",
+		"publication_date":   "publication_date",
+		"publishing_company": "publishing_company",
+		"created_at":         "created_at",
+		"updated_at":         "updated_at",
+	}
+	BookAssociations = map[string]string{
+		"authors": "Authors",
+	}
+)
+
+// AuthorColumns and AuthorAssociations do the same for Author.
+var (
+	AuthorColumns = map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"surname":    "surname",
+		"email":      "email",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	AuthorAssociations = map[string]string{
+		"books_published": "BooksPublished",
+	}
+)
+
+// QueryOptions narrows a read to the requested fields and associations. The
+// zero value reads every column and every association.
+type QueryOptions struct {
+	// Fields lists JSON field names; empty means all of them.
+	Fields []string
+	// Include lists association names; nil means every association unless
+	// Fields is set, in which case only the associations named in Fields.
+	Include []string
+	// Depth is the number of association levels to load; zero means one.
+	Depth int
+	// Limit caps the number of results of a search, which are then ordered
+	// by id and start after Offset of them; zero means no cap.
+	Limit  int
+	Offset int
+}
+
+// ParseQueryOptions reads the comma separated fields and include query
+// parameters, rejecting names not in columns or associations.
+func ParseQueryOptions(fields, include string, columns, associations map[string]string) (QueryOptions, error) {
+	var opts QueryOptions
+	for _, name := range split(fields) {
+		_, column := columns[name]
+		_, association := associations[name]
+		if !column && !association {
+			return QueryOptions{}, fmt.Errorf("%w: %q", ErrUnknownField, name)
+		}
+		opts.Fields = append(opts.Fields, name)
+	}
+	if include != "" {
+		opts.Include = []string{}
+	}
+	for _, name := range split(include) {
+		if _, ok := associations[name]; !ok {
+			return QueryOptions{}, fmt.Errorf("%w: %q", ErrUnknownField, name)
+		}
+		opts.Include = append(opts.Include, name)
+	}
+	return opts, nil
+}
+
+// ParsePage reads the limit and offset query parameters of a search. Both are
+// optional; limit must be between 1 and MaxLimit and offset not negative. An
+// offset without a limit pages by MaxLimit, as only paged searches skip
+// results.
+func ParsePage(limit, offset string) (int, int, error) {
+	var l, o int
+	var err error
+	if limit != "" {
+		if l, err = strconv.Atoi(limit); err != nil || l < 1 || l > MaxLimit {
+			return 0, 0, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, MaxLimit)
+		}
+	}
+	if offset != "" {
+		if o, err = strconv.Atoi(offset); err != nil || o < 0 {
+			return 0, 0, fmt.Errorf("%w: offset must not be negative", ErrInvalidPage)
+		}
+		if l == 0 {
+			l = MaxLimit
+		}
+	}
+	return l, o, nil
+}
+
+func split(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Sparse reports whether the options narrow the representation at all.
+func (o QueryOptions) Sparse() bool {
+	return len(o.Fields) > 0 || o.Include != nil
+}
+
+// Includes reports whether association should be loaded.
+func (o QueryOptions) Includes(association string) bool {
+	if !o.Sparse() {
+		return true
+	}
+	return contains(o.Include, association) || contains(o.Fields, association)
+}
+
+// Wants reports whether the response should carry field, given the
+// associations of the resource.
+func (o QueryOptions) Wants(field string, associations map[string]string) bool {
+	if _, ok := associations[field]; ok {
+		return o.Includes(field)
+	}
+	return len(o.Fields) == 0 || contains(o.Fields, field)
+}
+
+// Columns returns the columns to select, or nil for all of them. The primary
+// key and updated_at are always selected: associations are joined on the
+// former and Last-Modified is derived from the latter.
+func (o QueryOptions) Columns(columns map[string]string) []string {
+	if len(o.Fields) == 0 {
+		return nil
+	}
+	selected := []string{"id", "updated_at"}
+	for _, name := range o.Fields {
+		if column, ok := columns[name]; ok && !contains(selected, column) {
+			selected = append(selected, column)
+		}
+	}
+	return selected
+}
+
+// Path returns the preload path for association, alternating with its
+// inverse until Depth levels are covered, e.g. Authors.BooksPublished.
+func (o QueryOptions) Path(association, inverse string) string {
+	path := association
+	for level := 1; level < o.Depth; level++ {
+		if level%2 == 1 {
+			path += "." + inverse
+		} else {
+			path += "." + association
+		}
+	}
+	return path
+}
+
+// Key is a canonical encoding of the options, empty for the zero value, so
+// that equivalent requests share a cache entry.
+func (o QueryOptions) Key() string {
+	var parts []string
+	if o.Sparse() {
+		fields := append([]string(nil), o.Fields...)
+		sort.Strings(fields)
+		parts = append(parts, "fields="+strings.Join(fields, ","))
+	}
+	if o.Include != nil {
+		include := append([]string(nil), o.Include...)
+		sort.Strings(include)
+		parts = append(parts, "include="+strings.Join(include, ","))
+	}
+	if o.Depth > 1 {
+		parts = append(parts, "depth="+strconv.Itoa(o.Depth))
+	}
+	if o.Limit > 0 {
+		parts = append(parts, "limit="+strconv.Itoa(o.Limit), "offset="+strconv.Itoa(o.Offset))
+	}
+	return strings.Join(parts, "&")
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
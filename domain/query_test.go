@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryOptions(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("zero value reads everything", func(t *testing.T) {
+		opts, err := ParseQueryOptions("", "", BookColumns, BookAssociations)
+		as.NoError(err)
+		as.False(opts.Sparse())
+		as.True(opts.Includes("authors"))
+		as.Nil(opts.Columns(BookColumns))
+		as.Equal("", opts.Key())
+	})
+
+	t.Run("fields select columns and skip associations", func(t *testing.T) {
+		opts, err := ParseQueryOptions("title, ISBN", "", BookColumns, BookAssociations)
+		as.NoError(err)
+		as.Equal([]string{"id", "updated_at", "title", "isbn"}, opts.Columns(BookColumns))
+		as.False(opts.Includes("authors"))
+		as.True(opts.Wants("ISBN", BookAssociations))
+		as.False(opts.Wants("description", BookAssociations))
+		as.False(opts.Wants("authors", BookAssociations))
+	})
+
+	t.Run("include adds associations to the fields", func(t *testing.T) {
+		opts, err := ParseQueryOptions("id,title", "authors", BookColumns, BookAssociations)
+		as.NoError(err)
+		as.True(opts.Includes("authors"))
+		as.True(opts.Wants("authors", BookAssociations))
+		as.Equal("fields=id,title&include=authors", opts.Key())
+	})
+
+	t.Run("empty include drops associations", func(t *testing.T) {
+		opts, err := ParseQueryOptions("", ",", AuthorColumns, AuthorAssociations)
+		as.NoError(err)
+		as.False(opts.Includes("books_published"))
+		as.True(opts.Wants("email", AuthorAssociations))
+	})
+
+	t.Run("unknown names are rejected", func(t *testing.T) {
+		_, err := ParseQueryOptions("title,password", "", BookColumns, BookAssociations)
+		as.True(errors.Is(err, ErrUnknownField))
+		_, err = ParseQueryOptions("", "title", BookColumns, BookAssociations)
+		as.True(errors.Is(err, ErrUnknownField))
+	})
+}
+
+func TestQueryOptionsPath(t *testing.T) {
+	as := assert.New(t)
+	as.Equal("Authors", QueryOptions{}.Path("Authors", "BooksPublished"))
+	as.Equal("Authors.BooksPublished", QueryOptions{Depth: 2}.Path("Authors", "BooksPublished"))
+	as.Equal("Authors.BooksPublished.Authors", QueryOptions{Depth: 3}.Path("Authors", "BooksPublished"))
+	as.Equal("depth=2", QueryOptions{Depth: 2}.Key())
+}
+
+func TestParsePage(t *testing.T) {
+	as := assert.New(t)
+
+	limit, offset, err := ParsePage("", "")
+	as.NoError(err)
+	as.Zero(limit)
+	as.Zero(offset)
+
+	limit, offset, err = ParsePage("20", "40")
+	as.NoError(err)
+	as.Equal(20, limit)
+	as.Equal(40, offset)
+	as.Equal("limit=20&offset=40", QueryOptions{Limit: limit, Offset: offset}.Key())
+
+	// an offset alone is not dropped: it pages by MaxLimit
+	limit, offset, err = ParsePage("", "40")
+	as.NoError(err)
+	as.Equal(MaxLimit, limit)
+	as.Equal(40, offset)
+
+	for _, page := range [][2]string{{"0", ""}, {"101", ""}, {"x", ""}, {"", "-1"}} {
+		_, _, err = ParsePage(page[0], page[1])
+		as.ErrorIs(err, ErrInvalidPage, page)
+	}
+}
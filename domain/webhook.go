@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrUnknownEvent is returned when subscribing to an event that does not
+// exist.
+var ErrUnknownEvent = errors.New("unknown event type")
+
+// Webhook subscribes a URL to catalog events. Deliveries are signed with
+// Secret, which is only shown when the webhook is created.
+type Webhook struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	URL         string    `json:"url" gorm:"size:2048"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events" gorm:"serializer:json"`
+	Secret      string    `json:"-" gorm:"size:128"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook wants events of type eventType. An
+// event of "*" subscribes to every event.
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook, with the
+// outcome of the latest attempt. Pending deliveries are attempted at
+// NextAttemptAt.
+type WebhookDelivery struct {
+	ID             int             `json:"id" gorm:"primaryKey"`
+	WebhookID      int             `json:"webhook_id" gorm:"uniqueIndex:idx_webhook_deliveries_event,priority:1"`
+	EventID        string          `json:"event_id" gorm:"size:32;uniqueIndex:idx_webhook_deliveries_event,priority:2"`
+	EventType      string          `json:"event_type" gorm:"size:64"`
+	Payload        json.RawMessage `json:"payload" gorm:"type:mediumtext"`
+	Status         string          `json:"status" gorm:"size:16;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	ResponseStatus int             `json:"response_status"`
+	LastError      string          `json:"last_error" gorm:"size:1024"`
+	// RedeliveryOf is the delivery this one repeats, if any.
+	RedeliveryOf *int `json:"redelivery_of"`
+	// Original is true for the first delivery of an event to a webhook and
+	// null for redeliveries, which the unique index then leaves out.
+	Original    *bool      `json:"-" gorm:"uniqueIndex:idx_webhook_deliveries_event,priority:3"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type WebhookService interface {
+	// Create stores hook with a new signing secret and returns the secret,
+	// which is not retrievable afterwards.
+	Create(ctx context.Context, hook *Webhook) (string, error)
+	List(ctx context.Context) ([]Webhook, error)
+	Get(ctx context.Context, id string) (Webhook, error)
+	Delete(ctx context.Context, id string) error
+	// Deliveries returns the latest deliveries to webhook id, newest first.
+	Deliveries(ctx context.Context, id string, limit int) ([]WebhookDelivery, error)
+	// Redeliver queues the event of a past delivery to webhook id again.
+	Redeliver(ctx context.Context, id, deliveryID string) (WebhookDelivery, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *Webhook) error
+	Get(ctx context.Context, id string) (Webhook, error)
+	List(ctx context.Context) ([]Webhook, error)
+	Delete(ctx context.Context, id string) error
+	// CreateDeliveries skips the originals of deliveries the webhook already
+	// has for the same event, so that an event published again is not sent
+	// twice.
+	CreateDeliveries(ctx context.Context, deliveries []WebhookDelivery) error
+	GetDelivery(ctx context.Context, webhookID, id string) (WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID string, limit int) ([]WebhookDelivery, error)
+	// ClaimDue returns up to limit pending deliveries due at now and moves
+	// their next attempt to now+lease, so that other instances skip them
+	// while they are being sent.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"errors"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/render"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// EventReset tells a subscriber that events it asked to resume from are no
+// longer buffered.
+const EventReset = "reset"
+
+type EventHandler struct {
+	EventStream domain.EventStream
+	ChangeFeed  domain.ChangeFeed
+	// Heartbeat is how often an idle stream gets a comment, so that proxies
+	// keep it open.
+	Heartbeat time.Duration
+}
+
+func NewEventHandler(router *gin.Engine, es domain.EventStream, cf domain.ChangeFeed, heartbeat time.Duration) {
+	handler := &EventHandler{
+		EventStream: es,
+		ChangeFeed:  cf,
+		Heartbeat:   heartbeat,
+	}
+	api := router.Group("/api/v1")
+	api.GET("/events", handler.StreamEvents)
+	api.GET("/changes", handler.ListChanges)
+}
+
+// ListChanges returns up to ?limit changes to books and authors after the
+// one numbered ?since, in order, with next, the since of the following
+// page, and last_seq, the number of the latest change. Changes are kept for
+// the outbox retention; reading older ones returns 410 and the consumer has
+// to copy the catalog again.
+func (p *EventHandler) ListChanges(c *gin.Context) {
+	limit, _, err := domain.ParsePage(c.Query("limit"), "")
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if limit == 0 {
+		limit = domain.MaxLimit
+	}
+	since := 0
+	if s := c.Query("since"); s != "" {
+		if since, err = strconv.Atoi(s); err != nil || since < 0 {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "since must be a sequence number"})
+			return
+		}
+	}
+	var ctx = c.Request.Context()
+	events, last, err := p.ChangeFeed.Changes(ctx, since, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrChangesExpired):
+			render.Respond(c, http.StatusGone, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	changes, err := v1.NewChanges(events)
+	if err != nil {
+		render.Respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	next := since
+	if len(changes) > 0 {
+		next = changes[len(changes)-1].Seq
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": changes, "next": next, "last_seq": last})
+}
+
+// StreamEvents serves the catalog change events as Server-Sent Events, each
+// with its Seq as id, its type as event and its JSON as data. ?entity=book or
+// author selects the events about that entity, and ?id those about one of
+// them. Clients reconnecting with Last-Event-ID get the events they missed
+// while those are buffered, and a reset event otherwise. The stream ends
+// when the server shuts down.
+func (p *EventHandler) StreamEvents(c *gin.Context) {
+	var filter domain.EventFilter
+	filter.Entity = c.Query("entity")
+	if filter.Entity != "" && filter.Entity != domain.EntityBook && filter.Entity != domain.EntityAuthor {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "entity must be book or author"})
+		return
+	}
+	if id := c.Query("id"); id != "" {
+		if err := appvalidator.IsIDValid(id); err != nil {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if filter.Entity == "" {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "id requires entity"})
+			return
+		}
+		filter.ID, _ = strconv.Atoi(id)
+	}
+	after := 0
+	if lastID := c.GetHeader("Last-Event-ID"); lastID != "" {
+		if err := appvalidator.IsIDValid(lastID); err != nil {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		after, _ = strconv.Atoi(lastID)
+	}
+	var ctx = c.Request.Context()
+	sub, err := p.EventStream.Subscribe(ctx, after, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnavailable):
+			render.Respond(c, http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	defer p.EventStream.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if sub.Missed {
+		if err := sse.Encode(c.Writer, sse.Event{Event: EventReset, Data: gin.H{"reason": "events since the last event id are no longer buffered"}}); err != nil {
+			return
+		}
+	}
+	for _, event := range sub.Replay {
+		if err := encode(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(p.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			// closed when the server shuts down or the client fell behind;
+			// either way it reconnects with Last-Event-ID
+			if !ok {
+				return
+			}
+			if err := encode(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func encode(w gin.ResponseWriter, event domain.Event) error {
+	return sse.Encode(w, sse.Event{Id: strconv.Itoa(event.Seq), Event: event.Type, Data: event})
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStream replays sub and records what was subscribed to.
+type fakeStream struct {
+	sub    *domain.Subscription
+	after  int
+	filter domain.EventFilter
+}
+
+func (f *fakeStream) Subscribe(ctx context.Context, after int, filter domain.EventFilter) (*domain.Subscription, error) {
+	f.after, f.filter = after, filter
+	return f.sub, nil
+}
+
+func (f *fakeStream) Unsubscribe(sub *domain.Subscription) {}
+
+// fakeFeed returns events, or err, and records what was read.
+type fakeFeed struct {
+	events       []domain.Event
+	err          error
+	since, limit int
+}
+
+func (f *fakeFeed) Changes(ctx context.Context, since, limit int) ([]domain.Event, int, error) {
+	f.since, f.limit = since, limit
+	return f.events, 9, f.err
+}
+
+func TestStreamEvents(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	events := make(chan domain.Event, 1)
+	stream := &fakeStream{sub: &domain.Subscription{
+		Replay: []domain.Event{{ID: "evt_1", Type: domain.EventBookUpdated, Seq: 7, Data: map[string]int{"id": 2}}},
+		Missed: true,
+		Events: events,
+	}}
+	router := gin.New()
+	NewEventHandler(router, stream, &fakeFeed{}, time.Hour)
+
+	t.Run("happy path: streams the replay then live events until the stream ends", func(t *testing.T) {
+		events <- domain.Event{ID: "evt_2", Type: domain.EventBookDeleted, Seq: 8, Data: map[string]int{"id": 2}}
+		close(events)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/events?entity=book&id=2", nil)
+		req.Header.Set("Last-Event-ID", "5")
+		router.ServeHTTP(w, req)
+		body, _ := io.ReadAll(w.Body)
+		as.Equal(http.StatusOK, w.Code)
+		as.Equal("text/event-stream", w.Header().Get("Content-Type"))
+		as.Equal(5, stream.after)
+		as.Equal(domain.EventFilter{Entity: domain.EntityBook, ID: 2}, stream.filter)
+		as.Equal("event:reset\ndata:{\"reason\":\"events since the last event id are no longer buffered\"}\n\n"+
+			"id:7\nevent:book.updated\ndata:{\"id\":\"evt_1\",\"type\":\"book.updated\",\"occurred_at\":\"0001-01-01T00:00:00Z\",\"data\":{\"id\":2}}\n\n"+
+			"id:8\nevent:book.deleted\ndata:{\"id\":\"evt_2\",\"type\":\"book.deleted\",\"occurred_at\":\"0001-01-01T00:00:00Z\",\"data\":{\"id\":2}}\n\n", string(body))
+	})
+
+	t.Run("input error: invalid filters", func(t *testing.T) {
+		for _, query := range []string{"entity=publisher", "id=2", "entity=book&id=x"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events?"+query, nil))
+			as.Equal(http.StatusUnprocessableEntity, w.Code, query)
+		}
+	})
+}
+
+func TestListChanges(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	feed := &fakeFeed{events: []domain.Event{
+		{ID: "evt_1", Type: domain.EventBookCreated, Aggregate: "book/2", Seq: 4, Data: json.RawMessage(`{"id":2}`)},
+		{ID: "evt_2", Type: domain.EventBookDeleted, Aggregate: "book/2", Seq: 5, Data: json.RawMessage(`{"id":2}`)},
+		{ID: "evt_3", Type: domain.EventAuthorBooksChanged, Aggregate: "author/1", Seq: 6, Data: json.RawMessage(`{"id":1}`)},
+	}}
+	router := gin.New()
+	NewEventHandler(router, &fakeStream{}, feed, time.Hour)
+
+	t.Run("happy path: lists the changes with the next position", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/changes?since=3&limit=3", nil))
+		as.Equal(http.StatusOK, w.Code)
+		as.Equal(3, feed.since)
+		as.Equal(3, feed.limit)
+		var body struct {
+			Payload []v1.Change `json:"payload"`
+			Next    int         `json:"next"`
+			LastSeq int         `json:"last_seq"`
+		}
+		as.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+		as.Equal(6, body.Next)
+		as.Equal(9, body.LastSeq)
+		as.Len(body.Payload, 3)
+		as.Equal(v1.Change{Seq: 4, EventID: "evt_1", Type: domain.EventBookCreated, Operation: v1.OperationInsert, Entity: domain.EntityBook, ID: 2, Data: json.RawMessage(`{"id":2}`)}, body.Payload[0])
+		as.Equal(v1.OperationDelete, body.Payload[1].Operation)
+		as.Equal(json.RawMessage("null"), body.Payload[1].Data)
+		as.Equal(v1.OperationLink, body.Payload[2].Operation)
+		as.Equal(domain.EntityAuthor, body.Payload[2].Entity)
+	})
+
+	t.Run("happy path: defaults to the start and the largest page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/changes", nil))
+		as.Equal(http.StatusOK, w.Code)
+		as.Equal(0, feed.since)
+		as.Equal(domain.MaxLimit, feed.limit)
+	})
+
+	t.Run("input error: invalid since or limit", func(t *testing.T) {
+		for _, query := range []string{"since=-1", "since=x", "limit=0", "limit=101"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/changes?"+query, nil))
+			as.Equal(http.StatusUnprocessableEntity, w.Code, query)
+		}
+	})
+
+	t.Run("expired: changes no longer kept", func(t *testing.T) {
+		feed.err = domain.ErrChangesExpired
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/changes?since=1", nil))
+		as.Equal(http.StatusGone, w.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/authz"
+)
+
+type authorizedEventStream struct {
+	next   domain.EventStream
+	policy *authz.Policy
+}
+
+// NewAuthorizedEventStream wraps s so that following book events requires
+// the books:read permission and following author events authors:read; an
+// unfiltered subscription requires both.
+func NewAuthorizedEventStream(s domain.EventStream, policy *authz.Policy) domain.EventStream {
+	return &authorizedEventStream{next: s, policy: policy}
+}
+
+func (a *authorizedEventStream) Subscribe(ctx context.Context, after int, filter domain.EventFilter) (*domain.Subscription, error) {
+	if filter.Entity != domain.EntityAuthor {
+		if err := a.policy.Authorize(ctx, authz.BooksRead); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Entity != domain.EntityBook {
+		if err := a.policy.Authorize(ctx, authz.AuthorsRead); err != nil {
+			return nil, err
+		}
+	}
+	return a.next.Subscribe(ctx, after, filter)
+}
+
+func (a *authorizedEventStream) Unsubscribe(sub *domain.Subscription) {
+	a.next.Unsubscribe(sub)
+}
+
+type authorizedChangeFeed struct {
+	next   domain.ChangeFeed
+	policy *authz.Policy
+}
+
+// NewAuthorizedChangeFeed wraps f so that reading changes, which cover books
+// and authors, requires both books:read and authors:read.
+func NewAuthorizedChangeFeed(f domain.ChangeFeed, policy *authz.Policy) domain.ChangeFeed {
+	return &authorizedChangeFeed{next: f, policy: policy}
+}
+
+func (a *authorizedChangeFeed) Changes(ctx context.Context, since, limit int) ([]domain.Event, int, error) {
+	for _, permission := range []string{authz.BooksRead, authz.AuthorsRead} {
+		if err := a.policy.Authorize(ctx, permission); err != nil {
+			return nil, 0, err
+		}
+	}
+	return a.next.Changes(ctx, since, limit)
+}
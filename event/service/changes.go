@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"geniuscrew/domain"
+)
+
+type changeFeed struct {
+	outboxRepository domain.OutboxRepository
+}
+
+// NewChangeFeed returns the change feed of the events in the outbox, which
+// are kept for the outbox retention once sent.
+func NewChangeFeed(o domain.OutboxRepository) domain.ChangeFeed {
+	return &changeFeed{outboxRepository: o}
+}
+
+func (f *changeFeed) Changes(ctx context.Context, since, limit int) ([]domain.Event, int, error) {
+	records, err := f.outboxRepository.After(ctx, since, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	// sequence numbers have no gaps, so the next change is missing only when
+	// it was purged or not recorded yet
+	if len(records) == 0 || records[0].Seq != since+1 {
+		first, err := f.outboxRepository.FirstSeq(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if since+1 < first {
+			return nil, 0, fmt.Errorf("%w: changes after %d were purged, the oldest kept is %d", domain.ErrChangesExpired, since, first)
+		}
+	}
+	last, err := f.outboxRepository.LastSeq(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	events := make([]domain.Event, 0, len(records))
+	for _, r := range records {
+		events = append(events, r.Event())
+	}
+	return events, last, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanges(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	t.Run("happy path: returns the events after since", func(t *testing.T) {
+		outboxRepo := &repository.OutboxRepositoryMock{}
+		outboxRepo.On("After", ctx, 2, 10).Return([]domain.OutboxEvent{outboxEvent(3, "book/1"), outboxEvent(4, "author/1")}, nil).Once()
+		outboxRepo.On("LastSeq", ctx).Return(4, nil).Once()
+		events, last, err := NewChangeFeed(outboxRepo).Changes(ctx, 2, 10)
+		as.NoError(err)
+		as.Equal([]int{3, 4}, seqs(events))
+		as.Equal(4, last)
+		outboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("happy path: nothing new", func(t *testing.T) {
+		outboxRepo := &repository.OutboxRepositoryMock{}
+		outboxRepo.On("After", ctx, 4, 10).Return([]domain.OutboxEvent{}, nil).Once()
+		outboxRepo.On("FirstSeq", ctx).Return(3, nil).Once()
+		outboxRepo.On("LastSeq", ctx).Return(4, nil).Once()
+		events, last, err := NewChangeFeed(outboxRepo).Changes(ctx, 4, 10)
+		as.NoError(err)
+		as.Empty(events)
+		as.Equal(4, last)
+	})
+
+	t.Run("expired: events after since were purged", func(t *testing.T) {
+		outboxRepo := &repository.OutboxRepositoryMock{}
+		outboxRepo.On("After", ctx, 1, 10).Return([]domain.OutboxEvent{outboxEvent(3, "book/1")}, nil).Once()
+		outboxRepo.On("FirstSeq", ctx).Return(3, nil).Once()
+		_, _, err := NewChangeFeed(outboxRepo).Changes(ctx, 1, 10)
+		as.ErrorIs(err, domain.ErrChangesExpired)
+	})
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// pollBatch is the number of events read from the outbox at once.
+	pollBatch = 500
+	// subscriberBuffer is the number of events a subscriber may fall behind
+	// before it is dropped.
+	subscriberBuffer = 64
+)
+
+// StreamConfig tunes an event stream.
+type StreamConfig struct {
+	// BufferSize is the number of events kept for subscribers resuming
+	// from an earlier event.
+	BufferSize int
+	// PollInterval is how often the outbox is read for events recorded by
+	// any instance.
+	PollInterval time.Duration
+}
+
+type subscriber struct {
+	filter domain.EventFilter
+	events chan domain.Event
+}
+
+// Stream follows the outbox by Seq and fans the events out to subscribers.
+// Every instance follows the same outbox, so the Seq a subscriber resumes
+// from means the same on all of them.
+type Stream struct {
+	outboxRepository domain.OutboxRepository
+	cfg              StreamConfig
+	wake             chan struct{}
+	done             chan struct{}
+	closeOnce        sync.Once
+
+	mu sync.Mutex
+	// buffer holds the latest events, in order
+	buffer []domain.Event
+	// cursor is the Seq of the last event read. Only Poll changes it, so
+	// Poll may read it without holding mu.
+	cursor      int
+	primed      bool
+	subscribers map[*domain.Subscription]*subscriber
+}
+
+func NewStream(o domain.OutboxRepository, cfg StreamConfig) *Stream {
+	return &Stream{
+		outboxRepository: o,
+		cfg:              cfg,
+		wake:             make(chan struct{}, 1),
+		done:             make(chan struct{}),
+		subscribers:      make(map[*domain.Subscription]*subscriber),
+	}
+}
+
+// Notify makes Run read the outbox now rather than at the next poll.
+func (s *Stream) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run follows the outbox until ctx is done or Close is called.
+func (s *Stream) Run(ctx context.Context) {
+	defer s.Close()
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.Poll(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "reading outbox for the event stream failed", "layer", "service", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// Close ends every subscription and refuses new ones, so that streaming
+// responses finish before the server shuts down.
+func (s *Stream) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		close(s.done)
+		for sub, sb := range s.subscribers {
+			close(sb.events)
+			delete(s.subscribers, sub)
+		}
+	})
+}
+
+// Poll reads the events recorded since the last poll and hands them to the
+// subscribers. The first poll fills the buffer with the latest events. Polls
+// must not run concurrently.
+func (s *Stream) Poll(ctx context.Context) error {
+	if !s.primed {
+		latest, err := s.outboxRepository.Latest(ctx, s.cfg.BufferSize)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		for _, e := range latest {
+			s.buffer = append(s.buffer, e.Event())
+			s.cursor = e.Seq
+		}
+		s.primed = true
+		s.mu.Unlock()
+	}
+	for {
+		events, err := s.outboxRepository.After(ctx, s.cursor, pollBatch)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			s.publish(e.Event())
+		}
+		if len(events) < pollBatch {
+			return nil
+		}
+	}
+}
+
+// publish advances the cursor to event, buffers it and sends it to the
+// subscribers it matches, dropping those too far behind to take it.
+func (s *Stream) publish(event domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursor = event.Seq
+	s.buffer = append(s.buffer, event)
+	if over := len(s.buffer) - s.cfg.BufferSize; over > 0 {
+		s.buffer = append(s.buffer[:0], s.buffer[over:]...)
+	}
+	for sub, sb := range s.subscribers {
+		if !sb.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sb.events <- event:
+		default:
+			close(sb.events)
+			delete(s.subscribers, sub)
+		}
+	}
+}
+
+// Subscribe replays the buffered events with a Seq greater than after.
+func (s *Stream) Subscribe(ctx context.Context, after int, filter domain.EventFilter) (*domain.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil, domain.ErrUnavailable
+	default:
+	}
+	events := make(chan domain.Event, subscriberBuffer)
+	sub := &domain.Subscription{Events: events}
+	if after > 0 {
+		for _, e := range s.buffer {
+			if e.Seq > after && filter.Matches(e) {
+				sub.Replay = append(sub.Replay, e)
+			}
+		}
+		sub.Missed = after < s.cursor && (len(s.buffer) == 0 || s.buffer[0].Seq > after+1)
+	}
+	s.subscribers[sub] = &subscriber{filter: filter, events: events}
+	return sub, nil
+}
+
+func (s *Stream) Unsubscribe(sub *domain.Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sb, ok := s.subscribers[sub]; ok {
+		close(sb.events)
+		delete(s.subscribers, sub)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func outboxEvent(seq int, aggregate string) domain.OutboxEvent {
+	return domain.OutboxEvent{ID: seq, Seq: seq, EventID: "evt_" + aggregate, Type: domain.EventBookUpdated, Aggregate: aggregate, Data: []byte(`{}`)}
+}
+
+func seqs(events []domain.Event) []int {
+	out := []int{}
+	for _, e := range events {
+		out = append(out, e.Seq)
+	}
+	return out
+}
+
+func TestStream(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	outboxRepo := &repository.OutboxRepositoryMock{}
+	stream := NewStream(outboxRepo, StreamConfig{BufferSize: 3, PollInterval: time.Second})
+
+	outboxRepo.On("Latest", ctx, 3).Return([]domain.OutboxEvent{outboxEvent(1, "book/1"), outboxEvent(2, "author/1")}, nil).Once()
+	outboxRepo.On("After", ctx, 2, pollBatch).Return([]domain.OutboxEvent{}, nil).Once()
+	as.NoError(stream.Poll(ctx))
+
+	t.Run("happy path: replays buffered events after the last one seen", func(t *testing.T) {
+		sub, err := stream.Subscribe(ctx, 1, domain.EventFilter{})
+		as.NoError(err)
+		as.Equal([]int{2}, seqs(sub.Replay))
+		as.False(sub.Missed)
+		stream.Unsubscribe(sub)
+	})
+
+	t.Run("happy path: filters by entity and id", func(t *testing.T) {
+		all, _ := stream.Subscribe(ctx, 0, domain.EventFilter{})
+		books, _ := stream.Subscribe(ctx, 0, domain.EventFilter{Entity: domain.EntityBook})
+		book2, _ := stream.Subscribe(ctx, 0, domain.EventFilter{Entity: domain.EntityBook, ID: 2})
+		outboxRepo.On("After", ctx, 2, pollBatch).Return([]domain.OutboxEvent{outboxEvent(3, "book/2"), outboxEvent(4, "book/1"), outboxEvent(5, "author/1")}, nil).Once()
+		as.NoError(stream.Poll(ctx))
+
+		as.Equal([]int{3, 4, 5}, seqs(drain(all)))
+		as.Equal([]int{3, 4}, seqs(drain(books)))
+		as.Equal([]int{3}, seqs(drain(book2)))
+		for _, sub := range []*domain.Subscription{all, books, book2} {
+			stream.Unsubscribe(sub)
+		}
+	})
+
+	t.Run("resume: reports events evicted from the buffer", func(t *testing.T) {
+		// the buffer holds 3 to 5
+		sub, err := stream.Subscribe(ctx, 1, domain.EventFilter{})
+		as.NoError(err)
+		as.True(sub.Missed)
+		as.Equal([]int{3, 4, 5}, seqs(sub.Replay))
+		stream.Unsubscribe(sub)
+
+		sub, err = stream.Subscribe(ctx, 2, domain.EventFilter{})
+		as.NoError(err)
+		as.False(sub.Missed)
+		as.Equal([]int{3, 4, 5}, seqs(sub.Replay))
+		stream.Unsubscribe(sub)
+	})
+
+	t.Run("slow subscriber: is dropped instead of blocking the stream", func(t *testing.T) {
+		sub, _ := stream.Subscribe(ctx, 0, domain.EventFilter{})
+		for i := 0; i <= subscriberBuffer; i++ {
+			stream.publish(domain.Event{Seq: 100 + i, Aggregate: "book/1"})
+		}
+		as.Len(drain(sub), subscriberBuffer)
+		_, open := <-sub.Events
+		as.False(open)
+		stream.Unsubscribe(sub)
+	})
+
+	t.Run("close: ends subscriptions and refuses new ones", func(t *testing.T) {
+		sub, _ := stream.Subscribe(ctx, 0, domain.EventFilter{})
+		stream.Close()
+		_, open := <-sub.Events
+		as.False(open)
+		_, err := stream.Subscribe(ctx, 0, domain.EventFilter{})
+		as.True(errors.Is(err, domain.ErrUnavailable))
+	})
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestStreamConcurrentSubscribe(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	outboxRepo := &repository.OutboxRepositoryMock{}
+	stream := NewStream(outboxRepo, StreamConfig{BufferSize: 3, PollInterval: time.Second})
+	outboxRepo.On("Latest", ctx, 3).Return([]domain.OutboxEvent{outboxEvent(1, "book/1")}, nil).Once()
+	outboxRepo.On("After", ctx, mock.Anything, pollBatch).Return([]domain.OutboxEvent{outboxEvent(2, "book/1")}, nil)
+
+	// run with -race: Subscribe reads the cursor Poll advances
+	as.NoError(stream.Poll(ctx))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				as.NoError(stream.Poll(ctx))
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		sub, err := stream.Subscribe(ctx, 1, domain.EventFilter{})
+		as.NoError(err)
+		stream.Unsubscribe(sub)
+	}
+	close(done)
+	wg.Wait()
+}
+
+// drain returns the events waiting in sub.
+func drain(sub *domain.Subscription) []domain.Event {
+	var events []domain.Event
+	for {
+		select {
+		case e, ok := <-sub.Events:
+			if !ok {
+				return events
+			}
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type GraphQLHandler struct {
+	BookService   domain.BookService
+	AuthorService domain.AuthorService
+	Limits        Limits
+	schema        graphql.Schema
+}
+
+// NewGraphQLHandler serves queries and mutations over books and authors at
+// /graphql. Permissions are those of the services, as for the REST routes:
+// anonymous callers may run queries, and get 401 for mutations, provided
+// authentication lets them through to POST /graphql.
+func NewGraphQLHandler(router *gin.Engine, bs domain.BookService, as domain.AuthorService, limits Limits) error {
+	handler := &GraphQLHandler{
+		BookService:   bs,
+		AuthorService: as,
+		Limits:        limits,
+	}
+	schema, err := handler.newSchema()
+	if err != nil {
+		return err
+	}
+	handler.schema = schema
+	router.POST("/graphql", handler.Query)
+	return nil
+}
+
+func (p *GraphQLHandler) Query(c *gin.Context) {
+	var input struct {
+		Query         string                 `json:"query" binding:"required"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(input.Query), Name: "GraphQL request"})})
+	if err != nil {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	validation := graphql.ValidateDocument(&p.schema, doc, nil)
+	if !validation.IsValid {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+	if _, ok := auth.FromContext(c.Request.Context()); !ok && mutation(doc, input.OperationName) {
+		c.Header("WWW-Authenticate", `Bearer realm="geniuscrew"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+	if err := p.Limits.check(doc, input.OperationName, input.Variables); err != nil {
+		c.JSON(http.StatusOK, &graphql.Result{Errors: []gqlerrors.FormattedError{{
+			Message:    err.Error(),
+			Extensions: map[string]interface{}{"code": "QUERY_TOO_COMPLEX"},
+		}}})
+		return
+	}
+	var ctx = c.Request.Context()
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        p.schema,
+		AST:           doc,
+		OperationName: input.OperationName,
+		Args:          input.Variables,
+		Context:       withLoaders(ctx, p.BookService, p.AuthorService),
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// mutation reports whether the operation of doc that will run is a mutation.
+// It expects a validated document.
+func mutation(doc *ast.Document, operationName string) bool {
+	for _, definition := range doc.Definitions {
+		if d, ok := definition.(*ast.OperationDefinition); ok && (operationName == "" || (d.Name != nil && d.Name.Value == operationName)) {
+			return d.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
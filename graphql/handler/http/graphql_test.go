@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_authorService "geniuscrew/author/service"
+	_bookService "geniuscrew/book/service"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// post runs query as the caller principal, anonymously when nil.
+func post(t *testing.T, bookRepo *repository.BookRepositoryMock, authorRepo *repository.AuthorRepositoryMock, limits Limits, principal *auth.Principal, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if principal != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), *principal))
+		})
+	}
+	bookService := _bookService.NewBookService(bookRepo)
+	authorService := _authorService.NewAuthorService(authorRepo, &repository.AuthorBooksRepositoryMock{}, bookRepo)
+	assert.NoError(t, NewGraphQLHandler(router, bookService, authorService, limits))
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func serve(t *testing.T, bookRepo *repository.BookRepositoryMock, authorRepo *repository.AuthorRepositoryMock, limits Limits, query string) response {
+	rec := post(t, bookRepo, authorRepo, limits, &auth.Principal{Subject: "jane", Roles: []string{"editor"}}, query)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var out response
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	return out
+}
+
+func TestGraphQLBatchesAssociations(t *testing.T) {
+	as := assert.New(t)
+	bookRepo := &repository.BookRepositoryMock{}
+	authorRepo := &repository.AuthorRepositoryMock{}
+	firstPage := domain.QueryOptions{Include: []string{}, Limit: domain.MaxLimit}
+	bookRepo.On("GetByFilter", mock.Anything, "title", "go", firstPage).Return([]domain.Book{{ID: 1, Title: "Go"}, {ID: 2, Title: "Go again"}}, nil).Once()
+	bookRepo.On("GetByIDs", mock.Anything, []int{1, 2}, domain.QueryOptions{Fields: []string{"id"}, Include: []string{"authors"}}).Return([]domain.Book{
+		{ID: 1, Authors: []domain.Author{{ID: 7, Name: "John"}, {ID: 8, Name: "Jane"}}},
+		{ID: 2, Authors: []domain.Author{{ID: 7, Name: "John"}}},
+	}, nil).Once()
+	authorRepo.On("GetByIDs", mock.Anything, []int{7, 8}, domain.QueryOptions{Fields: []string{"id"}, Include: []string{"books_published"}}).Return([]domain.Author{
+		{ID: 7, BooksPublished: []domain.Book{{ID: 1}, {ID: 2}}},
+		{ID: 8, BooksPublished: []domain.Book{{ID: 1}}},
+	}, nil).Once()
+
+	out := serve(t, bookRepo, authorRepo, Limits{}, `{ books(field: TITLE, value: "go") { title authorCount authors { name bookCount } } }`)
+	as.Empty(out.Errors)
+	books := out.Data["books"].([]interface{})
+	as.Len(books, 2)
+	first := books[0].(map[string]interface{})
+	as.EqualValues(2, first["authorCount"])
+	as.EqualValues(2, first["authors"].([]interface{})[0].(map[string]interface{})["bookCount"])
+	bookRepo.AssertExpectations(t)
+	authorRepo.AssertExpectations(t)
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	as := assert.New(t)
+	query := `{ books(field: TITLE, value: "go", limit: 10) { authors { books { authors { name } } } } }`
+
+	out := serve(t, &repository.BookRepositoryMock{}, &repository.AuthorRepositoryMock{}, Limits{MaxDepth: 3}, query)
+	as.Len(out.Errors, 1)
+	as.Contains(out.Errors[0].Message, "depth 5")
+	as.Equal("QUERY_TOO_COMPLEX", out.Errors[0].Extensions["code"])
+
+	out = serve(t, &repository.BookRepositoryMock{}, &repository.AuthorRepositoryMock{}, Limits{MaxComplexity: 100}, query)
+	as.Len(out.Errors, 1)
+	as.Contains(out.Errors[0].Message, "complexity 11111")
+
+	// a search without a limit returns up to domain.MaxLimit entries
+	out = serve(t, &repository.BookRepositoryMock{}, &repository.AuthorRepositoryMock{}, Limits{MaxComplexity: 100}, `{ authors(field: NAME, value: "john") { name books { title } } }`)
+	as.Len(out.Errors, 1)
+	as.Contains(out.Errors[0].Message, "complexity 1201")
+}
+
+func TestGraphQLSearchPages(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: passes the page to the service", func(t *testing.T) {
+		authorRepo := &repository.AuthorRepositoryMock{}
+		authorRepo.On("GetByFilter", mock.Anything, "name", "john", domain.QueryOptions{Include: []string{}, Limit: 5, Offset: 10}).Return([]domain.Author{{ID: 7, Name: "John"}}, nil).Once()
+
+		out := serve(t, &repository.BookRepositoryMock{}, authorRepo, Limits{}, `{ authors(field: NAME, value: "john", limit: 5, offset: 10) { name } }`)
+		as.Empty(out.Errors)
+		as.Len(out.Data["authors"], 1)
+		authorRepo.AssertExpectations(t)
+	})
+
+	t.Run("happy path: complexity follows a limit given as a variable", func(t *testing.T) {
+		limits := Limits{MaxComplexity: 20}
+		doc, err := parser.Parse(parser.ParseParams{Source: `query($n: Int) { books(field: TITLE, value: "go", limit: $n) { title } }`})
+		as.NoError(err)
+		as.NoError(limits.check(doc, "", map[string]interface{}{"n": float64(5)}))
+		as.ErrorContains(limits.check(doc, "", map[string]interface{}{"n": float64(50)}), "complexity 51")
+	})
+
+	t.Run("input error: limit over the largest page", func(t *testing.T) {
+		out := serve(t, &repository.BookRepositoryMock{}, &repository.AuthorRepositoryMock{}, Limits{}, `{ books(field: TITLE, value: "go", limit: 101) { title } }`)
+		as.Len(out.Errors, 1)
+		as.Equal("BAD_USER_INPUT", out.Errors[0].Extensions["code"])
+	})
+}
+
+func TestGraphQLMutationErrors(t *testing.T) {
+	as := assert.New(t)
+	bookRepo := &repository.BookRepositoryMock{}
+	bookRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrDuplicateRecord).Once()
+
+	out := serve(t, bookRepo, &repository.AuthorRepositoryMock{}, Limits{}, `mutation { createBook(input: {title: "Go"}) { id } }`)
+	as.Len(out.Errors, 1)
+	as.Equal("BAD_USER_INPUT", out.Errors[0].Extensions["code"])
+	as.Contains(out.Errors[0].Extensions["fields"], "ISBN")
+
+	out = serve(t, bookRepo, &repository.AuthorRepositoryMock{}, Limits{}, `mutation { createBook(input: {title: "Go", description: "d", isbn: "978160309028", publishingCompany: "p"}) { id } }`)
+	as.Len(out.Errors, 1)
+	as.Equal("CONFLICT", out.Errors[0].Extensions["code"])
+	bookRepo.AssertExpectations(t)
+}
+
+func TestGraphQLAnonymous(t *testing.T) {
+	as := assert.New(t)
+	bookRepo := &repository.BookRepositoryMock{}
+	bookRepo.On("Get", mock.Anything, "1", mock.Anything).Return(domain.Book{ID: 1, Title: "Go"}, nil).Once()
+
+	rec := post(t, bookRepo, &repository.AuthorRepositoryMock{}, Limits{}, nil, `{ book(id: 1) { title } }`)
+	as.Equal(http.StatusOK, rec.Code)
+	as.Contains(rec.Body.String(), `"title":"Go"`)
+
+	rec = post(t, bookRepo, &repository.AuthorRepositoryMock{}, Limits{}, nil, `mutation { deleteBook(id: 1) }`)
+	as.Equal(http.StatusUnauthorized, rec.Code)
+	bookRepo.AssertExpectations(t)
+}
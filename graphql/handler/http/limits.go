@@ -0,0 +1,127 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"geniuscrew/domain"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listFactor is the number of entries an association list is assumed to
+// return when estimating the complexity of a query.
+const listFactor = 10
+
+// listFields return lists wherever they appear in the schema.
+var listFields = map[string]bool{"books": true, "authors": true}
+
+// Limits bounds the queries the endpoint executes. Depth counts nested
+// fields; complexity counts fields, multiplying those under a list by the
+// number of entries it may return: the limit of a search, and listFactor for
+// an association. Zero disables a limit. Introspection fields are not counted.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// check measures the operation that will run with variables and rejects it
+// when it goes over a limit. It expects a validated document.
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	var operations []*ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operations = append(operations, d)
+			}
+		}
+	}
+	for _, operation := range operations {
+		m := measure{fragments: fragments, variables: variables}
+		depth, complexity := m.selections(operation.SelectionSet, 1, true)
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections returns the depth and the complexity of set, whose fields each
+// cost multiplier; root tells whether set selects the fields of the
+// operation.
+func (m measure) selections(set *ast.SelectionSet, multiplier int, root bool) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			inner := multiplier
+			if listFields[s.Name.Value] {
+				inner *= m.listSize(s, root)
+			}
+			d, c = m.selections(s.SelectionSet, inner, false)
+			d, c = d+1, c+multiplier
+		case *ast.InlineFragment:
+			d, c = m.selections(s.SelectionSet, multiplier, root)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[s.Name.Value]; ok {
+				d, c = m.selections(fragment.SelectionSet, multiplier, root)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// listSize returns the number of entries list field s may return. Searches,
+// at the root, return their limit argument, domain.MaxLimit when it is unset
+// or out of range; associations are assumed to have listFactor entries.
+func (m measure) listSize(s *ast.Field, root bool) int {
+	if !root {
+		return listFactor
+	}
+	limit := domain.MaxLimit
+	for _, arg := range s.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				limit = n
+			}
+		case *ast.Variable:
+			switch n := m.variables[v.Name.Value].(type) {
+			case float64:
+				limit = int(n)
+			case int:
+				limit = n
+			}
+		}
+	}
+	if limit < 1 || limit > domain.MaxLimit {
+		return domain.MaxLimit
+	}
+	return limit
+}
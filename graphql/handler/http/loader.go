@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"sync"
+)
+
+// batch collects the keys requested while one level of a query resolves and
+// fetches them all with a single call the first time any result is read.
+// graphql-go resolves a level's thunks only after the whole level has been
+// walked, so every key of the level is pending by then.
+type batch[V any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, keys []int) (map[int]V, error)
+	pending []int
+	queued  map[int]bool
+	results map[int]V
+	errs    map[int]error
+}
+
+func newBatch[V any](fetch func(ctx context.Context, keys []int) (map[int]V, error)) *batch[V] {
+	return &batch[V]{fetch: fetch, queued: make(map[int]bool), results: make(map[int]V), errs: make(map[int]error)}
+}
+
+// Load queues key and returns a thunk yielding its value.
+func (b *batch[V]) Load(ctx context.Context, key int) func() (interface{}, error) {
+	b.mu.Lock()
+	if !b.queued[key] {
+		b.queued[key] = true
+		b.pending = append(b.pending, key)
+	}
+	b.mu.Unlock()
+	return func() (interface{}, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if len(b.pending) > 0 {
+			keys := b.pending
+			b.pending = nil
+			found, err := b.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					b.errs[k] = err
+					continue
+				}
+				b.results[k] = found[k]
+			}
+		}
+		if err := b.errs[key]; err != nil {
+			return nil, err
+		}
+		return b.results[key], nil
+	}
+}
+
+// loaders are the batches of one request.
+type loaders struct {
+	authorsOfBook *batch[[]domain.Author]
+	booksOfAuthor *batch[[]domain.Book]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, bs domain.BookService, as domain.AuthorService) context.Context {
+	l := &loaders{
+		authorsOfBook: newBatch(func(ctx context.Context, ids []int) (map[int][]domain.Author, error) {
+			books, err := bs.GetByIDs(ctx, ids, domain.QueryOptions{Fields: []string{"id"}, Include: []string{"authors"}})
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int][]domain.Author, len(books))
+			for _, book := range books {
+				out[book.ID] = book.Authors
+			}
+			return out, nil
+		}),
+		booksOfAuthor: newBatch(func(ctx context.Context, ids []int) (map[int][]domain.Book, error) {
+			authors, err := as.GetByIDs(ctx, ids, domain.QueryOptions{Fields: []string{"id"}, Include: []string{"books_published"}})
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int][]domain.Book, len(authors))
+			for _, author := range authors {
+				out[author.ID] = author.BooksPublished
+			}
+			return out, nil
+		}),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,470 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+
+	"github.com/graphql-go/graphql"
+)
+
+// codedError carries a machine readable code, and field errors for invalid
+// input, in the extensions of a GraphQL error.
+type codedError struct {
+	message string
+	code    string
+	fields  map[string]string
+}
+
+func (e codedError) Error() string {
+	return e.message
+}
+
+func (e codedError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	if e.fields != nil {
+		extensions["fields"] = e.fields
+	}
+	return extensions
+}
+
+// resolverError maps a service error to a coded GraphQL error.
+func resolverError(err error) error {
+	code := "INTERNAL"
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound), errors.Is(err, domain.ErrBookNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, domain.ErrDuplicateRecord):
+		code = "CONFLICT"
+	case errors.Is(err, domain.ErrUnauthorized):
+		code = "UNAUTHENTICATED"
+	case errors.Is(err, domain.ErrForbidden):
+		code = "FORBIDDEN"
+	case errors.Is(err, domain.ErrUnavailable):
+		code = "UNAVAILABLE"
+	case errors.Is(err, context.DeadlineExceeded):
+		code = "TIMEOUT"
+	}
+	return codedError{message: err.Error(), code: code}
+}
+
+func invalidInput(fields map[string]string) error {
+	return codedError{message: "invalid input", code: "BAD_USER_INPUT", fields: fields}
+}
+
+func (p *GraphQLHandler) newSchema() (graphql.Schema, error) {
+	var bookType, authorType *graphql.Object
+	bookType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: book(func(b domain.Book) interface{} { return b.ID })},
+				"title":             &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: book(func(b domain.Book) interface{} { return b.Title })},
+				"description":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: book(func(b domain.Book) interface{} { return b.Description })},
+				"isbn":              &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: book(func(b domain.Book) interface{} { return b.ISBN })},
+				"publicationDate":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: book(func(b domain.Book) interface{} { return b.PublicationDate })},
+				"publishingCompany": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: book(func(b domain.Book) interface{} { return b.PublishingCompany })},
+				"createdAt":         &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: book(func(b domain.Book) interface{} { return b.CreatedAt })},
+				"updatedAt":         &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: book(func(b domain.Book) interface{} { return b.UpdatedAt })},
+				"authors": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(authorType))),
+					Resolve: p.authorsOfBook(func(authors []domain.Author) interface{} { return authors }),
+				},
+				"authorCount": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.Int),
+					Resolve: p.authorsOfBook(func(authors []domain.Author) interface{} { return len(authors) }),
+				},
+			}
+		}),
+	})
+	authorType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: author(func(a domain.Author) interface{} { return a.ID })},
+				"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: author(func(a domain.Author) interface{} { return a.Name })},
+				"surname":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: author(func(a domain.Author) interface{} { return a.Surname })},
+				"email":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: author(func(a domain.Author) interface{} { return a.Email })},
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: author(func(a domain.Author) interface{} { return a.CreatedAt })},
+				"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime), Resolve: author(func(a domain.Author) interface{} { return a.UpdatedAt })},
+				"books": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))),
+					Resolve: p.booksOfAuthor(func(books []domain.Book) interface{} { return books }),
+				},
+				"bookCount": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.Int),
+					Resolve: p.booksOfAuthor(func(books []domain.Book) interface{} { return len(books) }),
+				},
+			}
+		}),
+	})
+
+	bookFilter := graphql.NewEnum(graphql.EnumConfig{
+		Name:   "BookFilter",
+		Values: graphql.EnumValueConfigMap{"TITLE": {Value: "title"}, "DESCRIPTION": {Value: "description"}},
+	})
+	authorFilter := graphql.NewEnum(graphql.EnumConfig{
+		Name:   "AuthorFilter",
+		Values: graphql.EnumValueConfigMap{"NAME": {Value: "name"}, "SURNAME": {Value: "surname"}, "EMAIL": {Value: "email"}},
+	})
+	bookInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":             {Type: graphql.String},
+			"description":       {Type: graphql.String},
+			"isbn":              {Type: graphql.String},
+			"publishingCompany": {Type: graphql.String},
+		},
+	})
+	authorInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AuthorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":    {Type: graphql.String},
+			"surname": {Type: graphql.String},
+			"email":   {Type: graphql.String},
+			"books":   {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "ISBNs of existing books"},
+		},
+	})
+	id := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	limit := &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: domain.MaxLimit, Description: fmt.Sprintf("at most %d", domain.MaxLimit)}
+	offset := &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"book":   &graphql.Field{Type: bookType, Args: graphql.FieldConfigArgument{"id": id}, Resolve: p.getBook},
+			"author": &graphql.Field{Type: authorType, Args: graphql.FieldConfigArgument{"id": id}, Resolve: p.getAuthor},
+			"books": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))),
+				Args: graphql.FieldConfigArgument{
+					"field":  {Type: graphql.NewNonNull(bookFilter)},
+					"value":  {Type: graphql.NewNonNull(graphql.String)},
+					"limit":  limit,
+					"offset": offset,
+				},
+				Resolve: p.searchBooks,
+			},
+			"authors": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(authorType))),
+				Args: graphql.FieldConfigArgument{
+					"field":  {Type: graphql.NewNonNull(authorFilter)},
+					"value":  {Type: graphql.NewNonNull(graphql.String)},
+					"limit":  limit,
+					"offset": offset,
+				},
+				Resolve: p.searchAuthors,
+			},
+		},
+	})
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type:    graphql.NewNonNull(bookType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(bookInput)}},
+				Resolve: p.createBook,
+			},
+			"updateBook": &graphql.Field{
+				Type:    graphql.NewNonNull(bookType),
+				Args:    graphql.FieldConfigArgument{"id": id, "input": {Type: graphql.NewNonNull(bookInput)}},
+				Resolve: p.updateBook,
+			},
+			"deleteBook": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": id},
+				Resolve: p.deleteBook,
+			},
+			"createAuthor": &graphql.Field{
+				Type:    graphql.NewNonNull(authorType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(authorInput)}},
+				Resolve: p.createAuthor,
+			},
+			"updateAuthor": &graphql.Field{
+				Type:    graphql.NewNonNull(authorType),
+				Args:    graphql.FieldConfigArgument{"id": id, "input": {Type: graphql.NewNonNull(authorInput)}},
+				Resolve: p.updateAuthor,
+			},
+			"deleteAuthor": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": id},
+				Resolve: p.deleteAuthor,
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func book(field func(domain.Book) interface{}) graphql.FieldResolveFn {
+	return func(rp graphql.ResolveParams) (interface{}, error) {
+		return field(rp.Source.(domain.Book)), nil
+	}
+}
+
+func author(field func(domain.Author) interface{}) graphql.FieldResolveFn {
+	return func(rp graphql.ResolveParams) (interface{}, error) {
+		return field(rp.Source.(domain.Author)), nil
+	}
+}
+
+// authorsOfBook resolves a field from the authors of the source book,
+// loading them in a batch unless they came with the book.
+func (p *GraphQLHandler) authorsOfBook(field func([]domain.Author) interface{}) graphql.FieldResolveFn {
+	return func(rp graphql.ResolveParams) (interface{}, error) {
+		source := rp.Source.(domain.Book)
+		if source.Authors != nil {
+			return field(source.Authors), nil
+		}
+		thunk := loadersFrom(rp.Context).authorsOfBook.Load(rp.Context, source.ID)
+		return func() (interface{}, error) {
+			authors, err := thunk()
+			if err != nil {
+				return nil, resolverError(err)
+			}
+			list, _ := authors.([]domain.Author)
+			return field(list), nil
+		}, nil
+	}
+}
+
+// booksOfAuthor resolves a field from the books of the source author,
+// loading them in a batch unless they came with the author.
+func (p *GraphQLHandler) booksOfAuthor(field func([]domain.Book) interface{}) graphql.FieldResolveFn {
+	return func(rp graphql.ResolveParams) (interface{}, error) {
+		source := rp.Source.(domain.Author)
+		if source.BooksPublished != nil {
+			return field(source.BooksPublished), nil
+		}
+		thunk := loadersFrom(rp.Context).booksOfAuthor.Load(rp.Context, source.ID)
+		return func() (interface{}, error) {
+			books, err := thunk()
+			if err != nil {
+				return nil, resolverError(err)
+			}
+			list, _ := books.([]domain.Book)
+			return field(list), nil
+		}, nil
+	}
+}
+
+// withoutAssociations reads the columns only; associations come from the
+// batch loaders when the query asks for them.
+var withoutAssociations = domain.QueryOptions{Include: []string{}}
+
+func idArg(rp graphql.ResolveParams) (string, error) {
+	id, _ := rp.Args["id"].(string)
+	if err := appvalidator.IsIDValid(id); err != nil {
+		return "", invalidInput(map[string]string{"id": err.Error()})
+	}
+	return id, nil
+}
+
+func (p *GraphQLHandler) getBook(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	b, err := p.BookService.Get(rp.Context, id, withoutAssociations)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return b, nil
+}
+
+func (p *GraphQLHandler) getAuthor(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	a, err := p.AuthorService.Get(rp.Context, id, withoutAssociations)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return a, nil
+}
+
+// pageArgs returns the options of a search: its columns, ordered by id, in a
+// page of limit entries after offset.
+func pageArgs(rp graphql.ResolveParams) (domain.QueryOptions, error) {
+	limit, _ := rp.Args["limit"].(int)
+	offset, _ := rp.Args["offset"].(int)
+	if limit < 1 || limit > domain.MaxLimit {
+		return domain.QueryOptions{}, invalidInput(map[string]string{"limit": fmt.Sprintf("must be between 1 and %d", domain.MaxLimit)})
+	}
+	if offset < 0 {
+		return domain.QueryOptions{}, invalidInput(map[string]string{"offset": "must not be negative"})
+	}
+	opts := withoutAssociations
+	opts.Limit, opts.Offset = limit, offset
+	return opts, nil
+}
+
+func (p *GraphQLHandler) searchBooks(rp graphql.ResolveParams) (interface{}, error) {
+	opts, err := pageArgs(rp)
+	if err != nil {
+		return nil, err
+	}
+	books, err := p.BookService.GetByFilter(rp.Context, rp.Args["field"].(string), rp.Args["value"].(string), opts)
+	if errors.Is(err, domain.ErrBookNotFound) {
+		return []domain.Book{}, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return books, nil
+}
+
+func (p *GraphQLHandler) searchAuthors(rp graphql.ResolveParams) (interface{}, error) {
+	opts, err := pageArgs(rp)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := p.AuthorService.GetByFilter(rp.Context, rp.Args["field"].(string), rp.Args["value"].(string), opts)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return authors, nil
+}
+
+func stringArg(input map[string]interface{}, name string) string {
+	s, _ := input[name].(string)
+	return s
+}
+
+func stringsArg(input map[string]interface{}, name string) []string {
+	list, _ := input[name].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		s, _ := v.(string)
+		out = append(out, s)
+	}
+	return out
+}
+
+func (p *GraphQLHandler) createBook(rp graphql.ResolveParams) (interface{}, error) {
+	input := rp.Args["input"].(map[string]interface{})
+	request := v1.CreateBookRequest{
+		Title:             stringArg(input, "title"),
+		Description:       stringArg(input, "description"),
+		ISBN:              stringArg(input, "isbn"),
+		PublishingCompany: stringArg(input, "publishingCompany"),
+	}
+	if inputErr := appvalidator.InputValidator(request); inputErr != nil {
+		return nil, invalidInput(inputErr)
+	}
+	b := request.Book()
+	if err := p.BookService.Create(rp.Context, &b); err != nil {
+		return nil, resolverError(err)
+	}
+	return b, nil
+}
+
+func (p *GraphQLHandler) updateBook(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	input := rp.Args["input"].(map[string]interface{})
+	request := v1.UpdateBookRequest{
+		Title:             stringArg(input, "title"),
+		Description:       stringArg(input, "description"),
+		ISBN:              stringArg(input, "isbn"),
+		PublishingCompany: stringArg(input, "publishingCompany"),
+	}
+	if inputErr := appvalidator.InputValidator(request); inputErr != nil {
+		return nil, invalidInput(inputErr)
+	}
+	current, err := p.BookService.Get(rp.Context, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if err := p.BookService.Update(rp.Context, id, &current, request.Book()); err != nil {
+		return nil, resolverError(err)
+	}
+	updated, err := p.BookService.Get(rp.Context, id, withoutAssociations)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return updated, nil
+}
+
+func (p *GraphQLHandler) deleteBook(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	var b domain.Book
+	if err := p.BookService.Delete(rp.Context, id, &b); err != nil {
+		return nil, resolverError(err)
+	}
+	return true, nil
+}
+
+func (p *GraphQLHandler) createAuthor(rp graphql.ResolveParams) (interface{}, error) {
+	input := rp.Args["input"].(map[string]interface{})
+	request := v1.CreateAuthorRequest{
+		Name:           stringArg(input, "name"),
+		Surname:        stringArg(input, "surname"),
+		Email:          stringArg(input, "email"),
+		BooksPublished: stringsArg(input, "books"),
+	}
+	if inputErr := appvalidator.InputValidator(request); inputErr != nil {
+		return nil, invalidInput(inputErr)
+	}
+	a := request.Author()
+	if err := p.AuthorService.Create(rp.Context, request.BooksPublished, &a); err != nil {
+		return nil, resolverError(err)
+	}
+	return a, nil
+}
+
+func (p *GraphQLHandler) updateAuthor(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	input := rp.Args["input"].(map[string]interface{})
+	request := v1.UpdateAuthorRequest{
+		Name:    stringArg(input, "name"),
+		Surname: stringArg(input, "surname"),
+		Email:   stringArg(input, "email"),
+	}
+	if _, ok := input["books"]; ok {
+		request.BooksPublished = stringsArg(input, "books")
+	}
+	if inputErr := appvalidator.InputValidator(request); inputErr != nil {
+		return nil, invalidInput(inputErr)
+	}
+	current, err := p.AuthorService.Get(rp.Context, id, domain.QueryOptions{})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if err := p.AuthorService.Update(rp.Context, id, &current, request.Author(), request.BooksPublished); err != nil {
+		return nil, resolverError(err)
+	}
+	updated, err := p.AuthorService.Get(rp.Context, id, withoutAssociations)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return updated, nil
+}
+
+func (p *GraphQLHandler) deleteAuthor(rp graphql.ResolveParams) (interface{}, error) {
+	id, err := idArg(rp)
+	if err != nil {
+		return nil, err
+	}
+	var a domain.Author
+	if err := p.AuthorService.Delete(rp.Context, id, &a); err != nil {
+		return nil, resolverError(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+// Package app wires the catalog services into HTTP and gRPC servers and runs
+// them until the process is asked to stop. The binaries under cmd choose which
+// services to serve.
+package app
+
+import (
+	"context"
+	"fmt"
+	"geniuscrew/internal/config"
+	"geniuscrew/internal/health"
+	"geniuscrew/internal/logger"
+	"geniuscrew/internal/tracing"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
+)
+
+// Run serves what opts selects, configured from the environment and .env.
+func Run(opts Options) {
+	err := godotenv.Load()
+	if err != nil {
+		fatal("Error loading .env file", err)
+	}
+	level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("Invalid LOG_LEVEL", err)
+	}
+	slog.SetDefault(logger.New(os.Stdout, level))
+	slog.Info("Starting server...")
+
+	shutdownTracing, err := tracing.Init(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"), opts.Name)
+	if err != nil {
+		fatal("Unable to initialize tracing", err)
+	}
+
+	// quit is cancelled by a kill signal
+	quit, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// initialize data sources, giving up early if asked to stop while the
+	// database is still unreachable
+	ds, err := initDS(quit)
+
+	if err != nil {
+		fatal("Unable to initialize data sources", err)
+	}
+
+	checker, err := newChecker(ds)
+	if err != nil {
+		fatal("Unable to initialize health checks", err)
+	}
+	drainDelay, err := config.Duration("SHUTDOWN_DRAIN_DELAY", 0)
+	if err != nil {
+		fatal("Invalid SHUTDOWN_DRAIN_DELAY", err)
+	}
+
+	s, err := inject(ds, checker, opts)
+
+	if err != nil {
+		fatal("Failure to inject data sources", err)
+	}
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", os.Getenv("APP_BASE_URL"), os.Getenv("APP_PORT")),
+		Handler: s.router,
+	}
+	// event streams never go idle; ending them lets Shutdown complete
+	srv.RegisterOnShutdown(s.stream.Close)
+
+	// Graceful server shutdown
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Failed to initialize server", err)
+		}
+	}()
+
+	slog.Info("Listening", "addr", srv.Addr)
+
+	// gRPC is served on its own port, or not at all when GRPC_PORT is empty
+	if port := os.Getenv("GRPC_PORT"); port != "" {
+		addr := fmt.Sprintf("%s:%s", os.Getenv("APP_BASE_URL"), port)
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			fatal("Failed to listen for gRPC", err)
+		}
+		go func() {
+			if err := s.grpc.Serve(lis); err != nil {
+				fatal("Failed to serve gRPC", err)
+			}
+		}()
+		slog.Info("Listening for gRPC", "addr", addr)
+	}
+
+	// outbox events and webhook deliveries are sent until the servers have
+	// shut down; those still pending then are sent by another instance or
+	// after a restart
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	var dispatchers sync.WaitGroup
+	dispatchers.Add(3)
+	go func() {
+		defer dispatchers.Done()
+		s.stream.Run(dispatchCtx)
+	}()
+	go func() {
+		defer dispatchers.Done()
+		s.outbox.Run(dispatchCtx)
+	}()
+	go func() {
+		defer dispatchers.Done()
+		s.webhooks.Run(dispatchCtx)
+	}()
+	dispatched := make(chan struct{})
+	go func() {
+		dispatchers.Wait()
+		close(dispatched)
+	}()
+
+	// This blocks until a kill signal cancels quit
+	<-quit.Done()
+
+	// Fail readiness first so load balancers stop sending new requests,
+	// then give them drainDelay to notice before closing listeners
+	checker.ShutDown()
+	s.grpcHealth.Shutdown()
+	time.Sleep(drainDelay)
+
+	// The context is used to inform the server it has 5 seconds to finish
+	// the request it is currently handling
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Shutdown server
+	slog.Info("Shutting down server...")
+	if err := srv.Shutdown(ctx); err != nil {
+		fatal("Server forced to shutdown", err)
+	}
+	stopGRPC(ctx, s.grpc)
+	stopDispatch()
+	select {
+	case <-dispatched:
+	case <-ctx.Done():
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+}
+
+// newChecker builds the readiness checks from the data sources and the
+// maintenance settings.
+func newChecker(ds *DataSources) (*health.Checker, error) {
+	sqlDB, err := ds.MySQLDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	maintenance, err := config.Bool("MAINTENANCE_MODE", false)
+	if err != nil {
+		return nil, err
+	}
+	checker := health.NewChecker(sqlDB, os.Getenv("MAINTENANCE_FILE"))
+	checker.SetMigrated(ds.MigrationErr == nil)
+	checker.SetMaintenance(maintenance)
+	return checker, nil
+}
+
+// stopGRPC lets in-flight gRPC calls finish until ctx is done, then closes
+// the remaining connections.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// fatal logs msg with err and exits the process.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/config"
+	"geniuscrew/internal/idempotency"
+	"geniuscrew/internal/logger"
+	"geniuscrew/internal/metrics"
+	"geniuscrew/internal/tracing"
+	"log/slog"
+	"os"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type DataSources struct {
+	MySQLDB *gorm.DB
+	// MigrationErr is the error returned by the schema migration, if any.
+	MigrationErr error
+}
+
+// InitDS establishes connections to fields in dataSources
+func initDS(ctx context.Context) (*DataSources, error) {
+	slog.Info("Initializing data sources")
+	slowQuery, err := config.Duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	maxAttempts, err := config.Int("DB_CONNECT_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return nil, err
+	}
+	backoff, err := config.Duration("DB_CONNECT_BACKOFF", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	maxBackoff, err := config.Duration("DB_CONNECT_MAX_BACKOFF", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	// Initialize MySQLDB connection
+	dsn := os.Getenv("DB_USERNAME") + ":" + os.Getenv("DB_PASSWORD") + "@tcp" + "(" + os.Getenv("DB_HOST") + ":" + os.Getenv("DB_PORT") + ")/" + os.Getenv("DB_NAME") + "?" + "charset=utf8mb4&parseTime=True&loc=Local"
+	gormConfig := &gorm.Config{
+		Logger: logger.NewGormLogger(slog.Default(), slowQuery),
+	}
+	db, err := connect(ctx, func() (*gorm.DB, error) { return gorm.Open(mysql.Open(dsn), gormConfig) }, maxAttempts, backoff, maxBackoff)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := metrics.RegisterDBStats(sqlDB, os.Getenv("DB_NAME")); err != nil {
+		return nil, err
+	}
+	migrationErr := db.AutoMigrate(&domain.Book{}, &domain.Author{}, &domain.APIKey{}, &domain.Webhook{}, &domain.WebhookDelivery{}, &domain.OutboxEvent{}, &domain.EventSequence{}, &idempotency.Record{})
+	if migrationErr != nil {
+		slog.Error("Database migration failed", "error", migrationErr)
+	}
+
+	return &DataSources{
+		MySQLDB:      db,
+		MigrationErr: migrationErr,
+	}, nil
+}
+
+// connect calls open until it succeeds, waiting backoff between attempts and
+// doubling it up to maxBackoff, and gives up after maxAttempts or once ctx
+// is done.
+func connect(ctx context.Context, open func() (*gorm.DB, error), maxAttempts int, backoff, maxBackoff time.Duration) (*gorm.DB, error) {
+	for attempt := 1; ; attempt++ {
+		db, err := open()
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= maxAttempts {
+			return nil, fmt.Errorf("connecting to database after %d attempts: %w", attempt, err)
+		}
+		slog.Warn("Database unavailable, retrying", "attempt", attempt, "retry_in", backoff.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
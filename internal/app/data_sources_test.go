@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestConnect(t *testing.T) {
+	as := assert.New(t)
+	errDown := errors.New("connection refused")
+
+	t.Run("happy path: retries until the database is up", func(t *testing.T) {
+		attempts := 0
+		open := func() (*gorm.DB, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, errDown
+			}
+			return &gorm.DB{}, nil
+		}
+		db, err := connect(context.Background(), open, 5, time.Millisecond, time.Millisecond)
+		as.NoError(err)
+		as.NotNil(db)
+		as.Equal(3, attempts)
+	})
+
+	t.Run("database error: gives up after the last attempt", func(t *testing.T) {
+		attempts := 0
+		open := func() (*gorm.DB, error) {
+			attempts++
+			return nil, errDown
+		}
+		_, err := connect(context.Background(), open, 3, time.Millisecond, 2*time.Millisecond)
+		as.ErrorIs(err, errDown)
+		as.ErrorContains(err, "after 3 attempts")
+		as.Equal(3, attempts)
+	})
+
+	t.Run("server error: stops waiting once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		attempts := 0
+		open := func() (*gorm.DB, error) {
+			attempts++
+			return nil, errDown
+		}
+		_, err := connect(ctx, open, 10, time.Hour, time.Hour)
+		as.ErrorIs(err, context.Canceled)
+		as.Equal(1, attempts)
+	})
+}
@@ -0,0 +1,470 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	_mysqlAPIKeyRepo "geniuscrew/apikey/repository/mysql"
+	_mysqlAuthorRepo "geniuscrew/author/repository/mysql"
+	_httpBookRepo "geniuscrew/book/repository/http"
+	_mysqlBookRepo "geniuscrew/book/repository/mysql"
+	_mysqlOutboxRepo "geniuscrew/outbox/repository/mysql"
+	_mysqlWebhookRepo "geniuscrew/webhook/repository/mysql"
+
+	_apiKeyService "geniuscrew/apikey/service"
+	_authorService "geniuscrew/author/service"
+	_bookService "geniuscrew/book/service"
+	_eventService "geniuscrew/event/service"
+	_outboxService "geniuscrew/outbox/service"
+	_webhookService "geniuscrew/webhook/service"
+
+	_apiKeyHandler "geniuscrew/apikey/handler/http"
+	_authorHandler "geniuscrew/author/handler/http"
+	_bookHandler "geniuscrew/book/handler/http"
+	_eventHandler "geniuscrew/event/handler/http"
+	_graphqlHandler "geniuscrew/graphql/handler/http"
+	_webhookHandler "geniuscrew/webhook/handler/http"
+
+	_authorGRPCHandler "geniuscrew/author/handler/grpc"
+	_bookGRPCHandler "geniuscrew/book/handler/grpc"
+
+	"geniuscrew/api"
+	v1 "geniuscrew/api/v1"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/authz"
+	"geniuscrew/internal/cache"
+	"geniuscrew/internal/config"
+	"geniuscrew/internal/health"
+	"geniuscrew/internal/idempotency"
+	"geniuscrew/internal/metrics"
+	"geniuscrew/internal/middleware"
+	"geniuscrew/internal/ratelimit"
+	"geniuscrew/internal/rpc"
+	"geniuscrew/internal/txn"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servers are the entry points the services are wired into.
+type servers struct {
+	router *gin.Engine
+	grpc   *grpc.Server
+	// grpcHealth answers the gRPC health protocol for grpc
+	grpcHealth *grpchealth.Server
+	// outbox hands recorded events to the sinks while running
+	outbox *_outboxService.Dispatcher
+	// webhooks sends webhook deliveries while running
+	webhooks *_webhookService.Dispatcher
+	// stream follows the outbox for event stream subscribers while running
+	stream *_eventService.Stream
+}
+
+// Options select what a binary serves.
+type Options struct {
+	// Name identifies the binary in traces.
+	Name string
+	// Books and Authors enable the REST routes and gRPC services of each.
+	// GraphQL spans both and is only served when both are enabled.
+	Books   bool
+	Authors bool
+	// RemoteBooks makes the author service resolve books through the book
+	// service at BOOKS_SERVICE_URL instead of the local database.
+	RemoteBooks bool
+}
+
+func inject(d *DataSources, checker *health.Checker, opts Options) (*servers, error) {
+	requestTimeout, err := config.Duration("HTTP_REQUEST_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	routeTimeouts, err := config.RouteDurations("HTTP_ROUTE_TIMEOUTS")
+	if err != nil {
+		return nil, err
+	}
+	grpcTimeout, err := config.Duration("GRPC_REQUEST_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	jwtVerifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		HS256Secret:        os.Getenv("AUTH_JWT_HS256_SECRET"),
+		RS256PublicKeyFile: os.Getenv("AUTH_JWT_RS256_PUBLIC_KEY_FILE"),
+		JWKSFile:           os.Getenv("AUTH_JWT_JWKS_FILE"),
+		Issuer:             os.Getenv("AUTH_JWT_ISSUER"),
+		Audience:           os.Getenv("AUTH_JWT_AUDIENCE"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	policy, err := authz.LoadPolicy(os.Getenv("AUTHZ_POLICY_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	rateLimits, err := loadRateLimits()
+	if err != nil {
+		return nil, err
+	}
+	validateRequests, err := config.Bool("OPENAPI_VALIDATE_REQUESTS", false)
+	if err != nil {
+		return nil, err
+	}
+	nestingDepth, err := config.Int("API_NESTING_DEPTH", 1)
+	if err != nil {
+		return nil, err
+	}
+	if nestingDepth < 1 || nestingDepth > v1.MaxDepth {
+		return nil, fmt.Errorf("API_NESTING_DEPTH must be between 1 and %d", v1.MaxDepth)
+	}
+	mapper := v1.Mapper{Depth: nestingDepth}
+	graphqlMaxDepth, err := config.Int("GRAPHQL_MAX_DEPTH", 6)
+	if err != nil {
+		return nil, err
+	}
+	graphqlMaxComplexity, err := config.Int("GRAPHQL_MAX_COMPLEXITY", 5000)
+	if err != nil {
+		return nil, err
+	}
+	cacheTTL, err := config.Duration("CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	cacheMaxEntries, err := config.Int("CACHE_MAX_ENTRIES", 10000)
+	if err != nil {
+		return nil, err
+	}
+	cacheMaxBytes, err := config.Int("CACHE_MAX_BYTES", 64<<20)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyTTL, err := config.Duration("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyLock, err := config.Duration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	webhookConfig, err := loadWebhookConfig()
+	if err != nil {
+		return nil, err
+	}
+	streamConfig, heartbeat, err := loadStreamConfig()
+	if err != nil {
+		return nil, err
+	}
+	// the event stream lasts until the client leaves
+	if _, ok := routeTimeouts["GET /api/v1/events"]; !ok {
+		routeTimeouts["GET /api/v1/events"] = 0
+	}
+
+	/*
+	 * repository layer
+	 */
+	mysqlBookRepo := _mysqlBookRepo.NewMySqlBookRepository(d.MySQLDB)
+	mysqlAuthorRepo := _mysqlAuthorRepo.NewMySqlAuthorRepository(d.MySQLDB)
+	// the repository the author service looks books up in
+	authorBookRepo := mysqlBookRepo
+	if opts.RemoteBooks {
+		remote, err := loadRemoteBooks()
+		if err != nil {
+			return nil, err
+		}
+		authorBookRepo = _mysqlAuthorRepo.NewMySqlBookReplica(d.MySQLDB, remote)
+		// the local copies are only refreshed when linked; reads show the
+		// books as the book service has them now
+		mysqlAuthorRepo = _mysqlAuthorRepo.NewRemoteBooksAuthorRepository(mysqlAuthorRepo, remote)
+	}
+	mysqlAuthorBooksRepo := _mysqlAuthorRepo.NewMySqlAuthorBooksRepository(d.MySQLDB)
+	mysqlAPIKeyRepo := _mysqlAPIKeyRepo.NewMySqlAPIKeyRepository(d.MySQLDB)
+	mysqlWebhookRepo := _mysqlWebhookRepo.NewMySqlWebhookRepository(d.MySQLDB)
+	mysqlOutboxRepo := _mysqlOutboxRepo.NewMySqlOutboxRepository(d.MySQLDB)
+	transactor := txn.NewTransactor(d.MySQLDB)
+
+	/*
+	 * service layer
+	 */
+	// books and authors share one cache so that writes to either side
+	// invalidate what the other cached about it
+	cacheStore := cache.NewLRU(cacheMaxEntries, cacheMaxBytes)
+
+	// writes record their events in the outbox in the same transaction;
+	// the outbox dispatcher hands them to the sinks once committed, the
+	// webhook sink queueing deliveries that the webhook dispatcher sends
+	webhooks := _webhookService.NewDispatcher(mysqlWebhookRepo, webhookConfig)
+	outboxConfig, err := loadOutboxConfig(_webhookService.NewWebhookPublisher(mysqlWebhookRepo, webhooks.Notify))
+	if err != nil {
+		return nil, err
+	}
+	outbox := _outboxService.NewDispatcher(mysqlOutboxRepo, outboxConfig)
+	stream := _eventService.NewStream(mysqlOutboxRepo, streamConfig)
+	publisher := _outboxService.NewOutboxPublisher(mysqlOutboxRepo, transactor, func() {
+		outbox.Notify()
+		stream.Notify()
+	})
+
+	bookService := _bookService.NewBookService(mysqlBookRepo)
+	bookService = _bookService.NewPublishingBookService(bookService, transactor, publisher, mapper)
+	if cacheTTL > 0 {
+		bookService = _bookService.NewCachedBookService(bookService, cacheStore, cacheTTL)
+	}
+	bookService = _bookService.NewAuthorizedBookService(bookService, policy)
+	bookService = _bookService.NewTracedBookService(bookService)
+
+	authorService := _authorService.NewAuthorService(mysqlAuthorRepo, mysqlAuthorBooksRepo, authorBookRepo)
+	authorService = _authorService.NewPublishingAuthorService(authorService, transactor, publisher, mapper)
+	if opts.RemoteBooks {
+		// the book service is called before the transaction, not in it
+		authorService = _authorService.NewBookResolvingAuthorService(authorService, authorBookRepo)
+	}
+	if cacheTTL > 0 {
+		authorService = _authorService.NewCachedAuthorService(authorService, cacheStore, cacheTTL)
+	}
+	authorService = _authorService.NewAuthorizedAuthorService(authorService, policy)
+	authorService = _authorService.NewTracedAuthorService(authorService)
+
+	apiKeyService := _apiKeyService.NewAPIKeyService(mysqlAPIKeyRepo, transactor, policy)
+	apiKeyService = _apiKeyService.NewAuthorizedAPIKeyService(apiKeyService, policy)
+
+	webhookService := _webhookService.NewWebhookService(mysqlWebhookRepo, webhooks.Notify)
+	webhookService = _webhookService.NewAuthorizedWebhookService(webhookService, policy)
+
+	eventStream := _eventService.NewAuthorizedEventStream(stream, policy)
+	changeFeed := _eventService.NewAuthorizedChangeFeed(_eventService.NewChangeFeed(mysqlOutboxRepo), policy)
+
+	router := gin.New()
+	// client IPs, which anonymous clients are rate limited by, are only taken
+	// from X-Forwarded-For and X-Real-IP when sent by a trusted proxy
+	if err := router.SetTrustedProxies(config.List("HTTP_TRUSTED_PROXIES")); err != nil {
+		return nil, fmt.Errorf("HTTP_TRUSTED_PROXIES: %w", err)
+	}
+
+	router.Use(middleware.Tracing())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog(slog.Default()))
+	router.Use(middleware.Metrics())
+	router.Use(gin.Recovery())
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AddAllowHeaders("Authorization", auth.APIKeyHeader, middleware.RequestIDHeader, "If-None-Match", "If-Modified-Since", middleware.IdempotencyKeyHeader)
+	corsConfig.AddExposeHeaders(middleware.RequestIDHeader, "ETag", "Last-Modified", middleware.IdempotentReplayedHeader)
+	router.Use(cors.New(corsConfig))
+	router.Use(middleware.Timeout(requestTimeout, routeTimeouts))
+	authenticator := auth.NewAuthenticator(jwtVerifier, mysqlAPIKeyRepo)
+	rateLimitStore := ratelimit.NewMemoryStore()
+	// credentials are throttled by client IP before they are checked
+	router.Use(middleware.RateLimitCredentials(rateLimitStore, rateLimits[middleware.RateLimitAuth]))
+	// anonymous callers may run GraphQL queries, as they may GET
+	router.Use(middleware.Authenticate(authenticator, "POST /graphql"))
+	router.Use(middleware.RateLimit(rateLimitStore, rateLimits))
+	if idempotencyTTL > 0 {
+		router.Use(middleware.Idempotency(idempotency.NewMySQLStore(d.MySQLDB), idempotencyTTL, idempotencyLock))
+	}
+	if validateRequests {
+		doc, err := api.Load(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("loading the OpenAPI document: %w", err)
+		}
+		validate, err := middleware.ValidateRequests(doc)
+		if err != nil {
+			return nil, err
+		}
+		router.Use(validate)
+	}
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	health.NewHealthHandler(router, checker)
+	api.NewDocsHandler(router)
+	/*
+	 * handler layer
+	 */
+	grpcServer, grpcHealth := rpc.NewServer(authenticator, rateLimitStore, rateLimits, grpcTimeout)
+	if opts.Books {
+		_bookHandler.NewBookHandler(router, bookService, mapper)
+		_bookGRPCHandler.NewBookServer(grpcServer, bookService, mapper)
+	}
+	if opts.Authors {
+		_authorHandler.NewAuthorHandler(router, authorService, mapper)
+		_authorGRPCHandler.NewAuthorServer(grpcServer, authorService, mapper)
+	}
+	_apiKeyHandler.NewAPIKeyHandler(router, apiKeyService)
+	_webhookHandler.NewWebhookHandler(router, webhookService)
+	_eventHandler.NewEventHandler(router, eventStream, changeFeed, heartbeat)
+	if opts.Books && opts.Authors {
+		err = _graphqlHandler.NewGraphQLHandler(router, bookService, authorService, _graphqlHandler.Limits{
+			MaxDepth:      graphqlMaxDepth,
+			MaxComplexity: graphqlMaxComplexity,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for name := range grpcServer.GetServiceInfo() {
+		grpcHealth.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	return &servers{router: router, grpc: grpcServer, grpcHealth: grpcHealth, outbox: outbox, webhooks: webhooks, stream: stream}, nil
+}
+
+// loadRateLimits reads the rate and burst of each rate limit class from
+// RATE_LIMIT_<CLASS>_RPS and RATE_LIMIT_<CLASS>_BURST. A burst of 0 disables
+// limiting for the class.
+func loadRateLimits() (map[string]ratelimit.Limit, error) {
+	defaults := map[string]ratelimit.Limit{
+		middleware.RateLimitRead:   {Rate: 20, Burst: 40},
+		middleware.RateLimitWrite:  {Rate: 5, Burst: 10},
+		middleware.RateLimitSearch: {Rate: 2, Burst: 5},
+		// above the other classes together, so that it only stops guessing
+		middleware.RateLimitAuth: {Rate: 50, Burst: 100},
+	}
+	limits := make(map[string]ratelimit.Limit)
+	for class, def := range defaults {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(class)
+		rate, err := config.Float(prefix+"_RPS", def.Rate)
+		if err != nil {
+			return nil, err
+		}
+		burst, err := config.Int(prefix+"_BURST", def.Burst)
+		if err != nil {
+			return nil, err
+		}
+		if burst > 0 && rate <= 0 {
+			return nil, fmt.Errorf("%s_RPS must be positive", prefix)
+		}
+		limits[class] = ratelimit.Limit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
+
+// loadWebhookConfig reads how webhook deliveries are sent from
+// WEBHOOK_TIMEOUT, WEBHOOK_MAX_ATTEMPTS, WEBHOOK_RETRY_BACKOFF,
+// WEBHOOK_MAX_BACKOFF and WEBHOOK_POLL_INTERVAL.
+func loadWebhookConfig() (_webhookService.DispatcherConfig, error) {
+	var cfg _webhookService.DispatcherConfig
+	var err error
+	if cfg.Timeout, err = config.Duration("WEBHOOK_TIMEOUT", 5*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxAttempts, err = config.Int("WEBHOOK_MAX_ATTEMPTS", 8); err != nil {
+		return cfg, err
+	}
+	if cfg.Backoff, err = config.Duration("WEBHOOK_RETRY_BACKOFF", 10*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxBackoff, err = config.Duration("WEBHOOK_MAX_BACKOFF", time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.PollInterval, err = config.Duration("WEBHOOK_POLL_INTERVAL", 5*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Timeout <= 0 || cfg.MaxAttempts < 1 || cfg.Backoff <= 0 || cfg.MaxBackoff < cfg.Backoff || cfg.PollInterval <= 0 {
+		return cfg, fmt.Errorf("WEBHOOK_* settings must be positive, with WEBHOOK_MAX_BACKOFF at least WEBHOOK_RETRY_BACKOFF")
+	}
+	return cfg, nil
+}
+
+// loadOutboxConfig reads the sinks of the outbox from OUTBOX_SINKS, a comma
+// separated list of log, webhook and file (appending to OUTBOX_FILE), and how
+// events are dispatched from OUTBOX_TIMEOUT, OUTBOX_RETRY_BACKOFF,
+// OUTBOX_MAX_BACKOFF, OUTBOX_POLL_INTERVAL and OUTBOX_RETENTION.
+func loadOutboxConfig(webhooks domain.EventPublisher) (_outboxService.DispatcherConfig, error) {
+	var cfg _outboxService.DispatcherConfig
+	sinks := os.Getenv("OUTBOX_SINKS")
+	if sinks == "" {
+		sinks = "webhook"
+	}
+	for _, name := range strings.Split(sinks, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "log":
+			cfg.Sinks = append(cfg.Sinks, _outboxService.Sink{Name: name, Publisher: _outboxService.NewLogSink()})
+		case "webhook":
+			cfg.Sinks = append(cfg.Sinks, _outboxService.Sink{Name: name, Publisher: webhooks})
+		case "file":
+			path := os.Getenv("OUTBOX_FILE")
+			if path == "" {
+				return cfg, fmt.Errorf("OUTBOX_FILE must be set for the file sink")
+			}
+			cfg.Sinks = append(cfg.Sinks, _outboxService.Sink{Name: name, Publisher: _outboxService.NewFileSink(path)})
+		default:
+			return cfg, fmt.Errorf("OUTBOX_SINKS: unknown sink %q, expected log, webhook or file", name)
+		}
+	}
+	var err error
+	if cfg.Timeout, err = config.Duration("OUTBOX_TIMEOUT", 5*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Backoff, err = config.Duration("OUTBOX_RETRY_BACKOFF", time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxBackoff, err = config.Duration("OUTBOX_MAX_BACKOFF", 5*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.PollInterval, err = config.Duration("OUTBOX_POLL_INTERVAL", 5*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Retention, err = config.Duration("OUTBOX_RETENTION", 7*24*time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.Timeout <= 0 || cfg.Backoff <= 0 || cfg.MaxBackoff < cfg.Backoff || cfg.PollInterval <= 0 || cfg.Retention <= 0 {
+		return cfg, fmt.Errorf("OUTBOX_* settings must be positive, with OUTBOX_MAX_BACKOFF at least OUTBOX_RETRY_BACKOFF")
+	}
+	return cfg, nil
+}
+
+// loadStreamConfig reads the event stream settings from EVENTS_BUFFER_SIZE,
+// EVENTS_POLL_INTERVAL and EVENTS_HEARTBEAT.
+func loadStreamConfig() (_eventService.StreamConfig, time.Duration, error) {
+	var cfg _eventService.StreamConfig
+	var err error
+	if cfg.BufferSize, err = config.Int("EVENTS_BUFFER_SIZE", 1000); err != nil {
+		return cfg, 0, err
+	}
+	if cfg.PollInterval, err = config.Duration("EVENTS_POLL_INTERVAL", time.Second); err != nil {
+		return cfg, 0, err
+	}
+	heartbeat, err := config.Duration("EVENTS_HEARTBEAT", 15*time.Second)
+	if err != nil {
+		return cfg, 0, err
+	}
+	if cfg.BufferSize < 1 || cfg.PollInterval <= 0 || heartbeat <= 0 {
+		return cfg, 0, fmt.Errorf("EVENTS_* settings must be positive")
+	}
+	return cfg, heartbeat, nil
+}
+
+// loadRemoteBooks configures the client of the book service from
+// BOOKS_SERVICE_URL, BOOKS_SERVICE_API_KEY, BOOKS_SERVICE_TIMEOUT,
+// BOOKS_SERVICE_RETRIES and BOOKS_SERVICE_RETRY_BACKOFF.
+func loadRemoteBooks() (domain.BookRepository, error) {
+	baseURL := os.Getenv("BOOKS_SERVICE_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("BOOKS_SERVICE_URL is required")
+	}
+	timeout, err := config.Duration("BOOKS_SERVICE_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	retries, err := config.Int("BOOKS_SERVICE_RETRIES", 2)
+	if err != nil {
+		return nil, err
+	}
+	backoff, err := config.Duration("BOOKS_SERVICE_RETRY_BACKOFF", 100*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	return _httpBookRepo.NewHTTPBookRepository(_httpBookRepo.Config{
+		BaseURL: baseURL,
+		APIKey:  os.Getenv("BOOKS_SERVICE_API_KEY"),
+		Timeout: timeout,
+		Retries: retries,
+		Backoff: backoff,
+	}), nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognise.
+const apiKeyPrefix = "gck"
+
+// GenerateAPIKey returns a new API key together with its public prefix and
+// the hash to store. The key itself is never stored.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	id := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err = rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(id)
+	key = apiKeyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded BLAKE2b-256 digest of key. API keys carry
+// 256 bits of randomness, so a fast hash is enough to make a leaked table
+// useless without making every request pay for a password hash.
+func HashAPIKey(key string) string {
+	sum := blake2b.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAPIKey extracts the prefix from key, reporting false when key is not
+// shaped like an API key.
+func ParseAPIKey(key string) (string, bool) {
+	// The secret is base64url encoded and may itself contain underscores.
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// APIKeyMatches compares key against a stored hash in constant time.
+func APIKeyMatches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
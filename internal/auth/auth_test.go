@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, c jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTVerifier(t *testing.T) {
+	as := assert.New(t)
+	exp := time.Now().Add(time.Hour).Unix()
+
+	t.Run("happy path: HS256 token with roles and scopes", func(t *testing.T) {
+		v, err := NewJWTVerifier(JWTConfig{HS256Secret: "secret", Issuer: "geniuscrew"})
+		as.NoError(err)
+		token := sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+			"sub": "jane", "iss": "geniuscrew", "exp": exp, "roles": []string{"editor"}, "scope": "books:read books:write",
+		})
+		p, err := v.Verify(token)
+		as.NoError(err)
+		as.Equal("jane", p.Subject)
+		as.Equal([]string{"editor"}, p.Roles)
+		as.Equal([]string{"books:read", "books:write"}, p.Scopes)
+		as.Equal(MethodJWT, p.Method)
+	})
+
+	t.Run("input error: wrong secret, expired token and wrong issuer", func(t *testing.T) {
+		v, err := NewJWTVerifier(JWTConfig{HS256Secret: "secret", Issuer: "geniuscrew"})
+		as.NoError(err)
+		_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("other"), "", jwt.MapClaims{"sub": "jane", "iss": "geniuscrew", "exp": exp}))
+		as.Error(err)
+		_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "jane", "iss": "geniuscrew", "exp": time.Now().Add(-time.Minute).Unix()}))
+		as.Error(err)
+		_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "jane", "iss": "someone-else", "exp": exp}))
+		as.Error(err)
+	})
+
+	t.Run("happy path: RS256 token verified against a JWKS file", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		as.NoError(err)
+		set := map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}}
+		data, _ := json.Marshal(set)
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		as.NoError(os.WriteFile(path, data, 0o600))
+
+		v, err := NewJWTVerifier(JWTConfig{JWKSFile: path})
+		as.NoError(err)
+		p, err := v.Verify(sign(t, jwt.SigningMethodRS256, key, "k1", jwt.MapClaims{"sub": "svc", "exp": exp}))
+		as.NoError(err)
+		as.Equal("svc", p.Subject)
+
+		_, err = v.Verify(sign(t, jwt.SigningMethodRS256, key, "unknown", jwt.MapClaims{"sub": "svc", "exp": exp}))
+		as.Error(err)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	as := assert.New(t)
+	verifier, err := NewJWTVerifier(JWTConfig{})
+	as.NoError(err)
+	key, prefix, hash, err := GenerateAPIKey()
+	as.NoError(err)
+
+	t.Run("happy path: valid api key", func(t *testing.T) {
+		keys := &repository.APIKeyRepositoryMock{}
+		keys.On("GetByPrefix", mock.Anything, prefix).Return(domain.APIKey{ID: 1, Owner: "importer", Prefix: prefix, Hash: hash, Roles: []string{"editor"}}, nil).Once()
+		keys.On("TouchLastUsed", mock.Anything, 1, mock.Anything).Return(nil).Once()
+		r := httptest.NewRequest("POST", "/api/v1/books", nil)
+		r.Header.Set(APIKeyHeader, key)
+		p, ok, err := NewAuthenticator(verifier, keys).Authenticate(r)
+		as.NoError(err)
+		as.True(ok)
+		as.Equal("importer", p.Subject)
+		as.Equal(prefix, p.KeyID)
+		keys.AssertExpectations(t)
+	})
+
+	t.Run("input error: revoked api key", func(t *testing.T) {
+		revoked := time.Now().Add(-time.Minute)
+		keys := &repository.APIKeyRepositoryMock{}
+		keys.On("GetByPrefix", mock.Anything, prefix).Return(domain.APIKey{ID: 1, Prefix: prefix, Hash: hash, RevokedAt: &revoked}, nil).Once()
+		r := httptest.NewRequest("DELETE", "/api/v1/books/1", nil)
+		r.Header.Set("Authorization", "Bearer "+key)
+		_, ok, err := NewAuthenticator(verifier, keys).Authenticate(r)
+		as.False(ok)
+		as.True(errors.Is(err, domain.ErrUnauthorized))
+		keys.AssertExpectations(t)
+	})
+
+	t.Run("no credentials: anonymous request", func(t *testing.T) {
+		keys := &repository.APIKeyRepositoryMock{}
+		_, ok, err := NewAuthenticator(verifier, keys).Authenticate(httptest.NewRequest("GET", "/api/v1/books/1", nil).WithContext(context.Background()))
+		as.NoError(err)
+		as.False(ok)
+	})
+}
+
+func TestParseAPIKey(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: secret containing underscores", func(t *testing.T) {
+		prefix, ok := ParseAPIKey("gck_0a1b2c3d4e5f_Ab_cD-e_fGh")
+		as.True(ok)
+		as.Equal("0a1b2c3d4e5f", prefix)
+	})
+
+	t.Run("input error: not shaped like an api key", func(t *testing.T) {
+		for _, key := range []string{"", "gck", "gck_0a1b2c3d4e5f", "gck__secret", "gck_0a1b2c3d4e5f_", "abc_0a1b2c3d4e5f_secret"} {
+			_, ok := ParseAPIKey(key)
+			as.False(ok, key)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"geniuscrew/domain"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIKeyHeader carries an API key. Keys are also accepted as a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// lastUsedResolution limits how often a key's last-used time is written.
+const lastUsedResolution = time.Minute
+
+// Authenticator resolves the credentials of a request to a Principal.
+type Authenticator struct {
+	jwt  *JWTVerifier
+	keys domain.APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAuthenticator(jwt *JWTVerifier, keys domain.APIKeyRepository) *Authenticator {
+	return &Authenticator{jwt: jwt, keys: keys, now: time.Now}
+}
+
+// Authenticate returns the caller of r. It reports false with a nil error when
+// r carries no credentials, and an error wrapping domain.ErrUnauthorized when
+// the credentials it carries are not valid.
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, bool, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		p, err := a.apiKey(r.Context(), key)
+		return p, err == nil, err
+	}
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Principal{}, false, nil
+	}
+	scheme, token, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Principal{}, false, fmt.Errorf("%w: unsupported authorization scheme", domain.ErrUnauthorized)
+	}
+	if _, ok := ParseAPIKey(token); ok {
+		p, err := a.apiKey(r.Context(), token)
+		return p, err == nil, err
+	}
+	p, err := a.jwt.Verify(token)
+	if err != nil {
+		return Principal{}, false, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+	return p, true, nil
+}
+
+func (a *Authenticator) apiKey(ctx context.Context, key string) (Principal, error) {
+	prefix, ok := ParseAPIKey(key)
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: malformed api key", domain.ErrUnauthorized)
+	}
+	stored, err := a.keys.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			return Principal{}, fmt.Errorf("%w: invalid api key", domain.ErrUnauthorized)
+		}
+		return Principal{}, err
+	}
+	now := a.now()
+	if !APIKeyMatches(key, stored.Hash) || !stored.Active(now) {
+		return Principal{}, fmt.Errorf("%w: invalid api key", domain.ErrUnauthorized)
+	}
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > lastUsedResolution {
+		if err := a.keys.TouchLastUsed(ctx, stored.ID, now); err != nil {
+			slog.WarnContext(ctx, "recording api key use failed", "key_id", stored.Prefix, "error", err)
+		}
+	}
+	return Principal{
+		Subject: stored.Owner,
+		Roles:   stored.Roles,
+		Scopes:  stored.Scopes,
+		Method:  MethodAPIKey,
+		KeyID:   stored.Prefix,
+	}, nil
+}
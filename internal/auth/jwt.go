@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig lists where verification keys come from. Any combination may be
+// set; a verifier without keys rejects every token.
+type JWTConfig struct {
+	// HS256Secret verifies HS256 tokens.
+	HS256Secret string
+	// RS256PublicKeyFile is a PEM encoded RSA public key verifying RS256 tokens.
+	RS256PublicKeyFile string
+	// JWKSFile is a JSON Web Key Set holding RSA and/or symmetric keys,
+	// selected by the token's kid header.
+	JWKSFile string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+}
+
+// claims are the registered claims plus the roles and scopes granted to the
+// subject. Scopes follow RFC 8693 and are space separated.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+	Scope string   `json:"scope"`
+}
+
+// JWTVerifier validates bearer tokens signed with HS256 or RS256.
+type JWTVerifier struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+	parser   *jwt.Parser
+}
+
+// NewJWTVerifier loads the keys named in cfg.
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmacKeys: make(map[string][]byte),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+	}
+	if cfg.HS256Secret != "" {
+		v.hmacKeys[""] = []byte(cfg.HS256Secret)
+	}
+	if cfg.RS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.RS256PublicKeyFile, err)
+		}
+		v.rsaKeys[""] = key
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.JWKSFile, err)
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Verify checks the token's signature and claims and returns its subject.
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, v.key); err != nil {
+		return Principal{}, err
+	}
+	if c.Subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+	return Principal{
+		Subject: c.Subject,
+		Roles:   c.Roles,
+		Scopes:  strings.Fields(c.Scope),
+		Method:  MethodJWT,
+	}, nil
+}
+
+// key selects the verification key by algorithm and kid. A token without a
+// kid uses the key configured outside the JWKS, or the only key of its type.
+func (v *JWTVerifier) key(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	switch t.Method.Alg() {
+	case "HS256":
+		if key, ok := lookup(v.hmacKeys, kid); ok {
+			return key, nil
+		}
+	case "RS256":
+		if key, ok := lookup(v.rsaKeys, kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s key for kid %q", t.Method.Alg(), kid)
+}
+
+func lookup[K any](keys map[string]K, kid string) (K, bool) {
+	if key, ok := keys[kid]; ok {
+		return key, true
+	}
+	var zero K
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	return zero, false
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		K   string `json:"k"`
+	} `json:"keys"`
+}
+
+func (v *JWTVerifier) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+			v.rsaKeys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+			v.hmacKeys[k.Kid] = secret
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package auth authenticates callers with JWT bearer tokens or API keys and
+// carries the caller's identity in the request context.
+package auth
+
+import "context"
+
+// Authentication methods recorded on a Principal.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+	Scopes  []string `json:"scopes"`
+	Method  string   `json:"method"`
+	// KeyID is the prefix of the API key used, empty for JWT callers.
+	KeyID string `json:"key_id,omitempty"`
+}
+
+type ctxKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the caller stored in ctx, and false for anonymous
+// requests.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
+
+// Subject names the caller for logs and audit records, "anonymous" when the
+// request carried no credentials.
+func Subject(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.Subject
+	}
+	return "anonymous"
+}
@@ -0,0 +1,136 @@
+// Package authz decides which catalog operations a caller may perform.
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Permissions checked by the catalog services.
+const (
+	BooksRead      = "books:read"
+	BooksCreate    = "books:create"
+	BooksUpdate    = "books:update"
+	BooksDelete    = "books:delete"
+	AuthorsRead    = "authors:read"
+	AuthorsCreate  = "authors:create"
+	AuthorsUpdate  = "authors:update"
+	AuthorsDelete  = "authors:delete"
+	APIKeysManage  = "apikeys:manage"
+	WebhooksManage = "webhooks:manage"
+)
+
+// ForbiddenError names the permission the caller lacks.
+type ForbiddenError struct {
+	Permission string
+}
+
+func (e *ForbiddenError) Error() string {
+	return "forbidden: missing permission " + e.Permission
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == domain.ErrForbidden
+}
+
+// Policy maps roles to the permissions they grant. A permission may end in
+// "*" to grant every permission with that prefix, e.g. "books:*" or "*".
+type Policy struct {
+	// AnonymousRole is granted to requests without credentials; empty grants
+	// nothing.
+	AnonymousRole string              `json:"anonymous_role" yaml:"anonymous_role"`
+	Roles         map[string][]string `json:"roles" yaml:"roles"`
+}
+
+// DefaultPolicy lets readers GET, editors create and update, and admins do
+// everything including delete.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		AnonymousRole: "reader",
+		Roles: map[string][]string{
+			"reader": {BooksRead, AuthorsRead},
+			"editor": {BooksRead, BooksCreate, BooksUpdate, AuthorsRead, AuthorsCreate, AuthorsUpdate},
+			"admin":  {"*"},
+		},
+	}
+}
+
+// LoadPolicy reads a policy from a YAML or JSON file, chosen by extension.
+// An empty path returns DefaultPolicy.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &p)
+	default:
+		err = yaml.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.AnonymousRole != "" {
+		if _, ok := p.Roles[p.AnonymousRole]; !ok {
+			return nil, fmt.Errorf("%s: anonymous role %q is not defined", path, p.AnonymousRole)
+		}
+	}
+	return &p, nil
+}
+
+// Authorize returns a *ForbiddenError unless the caller in ctx holds
+// permission. A caller holds a permission when one of its roles grants it and,
+// if the caller's credentials are limited to scopes, one scope covers it.
+// Callers without credentials, or whose credentials carry no role, get
+// AnonymousRole.
+func (p *Policy) Authorize(ctx context.Context, permission string) error {
+	principal, _ := auth.FromContext(ctx)
+	roles := principal.Roles
+	if len(roles) == 0 {
+		roles = []string{p.AnonymousRole}
+	}
+	if !p.granted(roles, permission) {
+		return &ForbiddenError{Permission: permission}
+	}
+	if len(principal.Scopes) > 0 && !anyMatch(principal.Scopes, permission) {
+		return &ForbiddenError{Permission: permission}
+	}
+	return nil
+}
+
+// Defines reports whether role is one of the roles of the policy.
+func (p *Policy) Defines(role string) bool {
+	_, ok := p.Roles[role]
+	return ok
+}
+
+func (p *Policy) granted(roles []string, permission string) bool {
+	for _, role := range roles {
+		if anyMatch(p.Roles[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyMatch(grants []string, permission string) bool {
+	for _, grant := range grants {
+		if grant == permission || (strings.HasSuffix(grant, "*") && strings.HasPrefix(permission, strings.TrimSuffix(grant, "*"))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	as := assert.New(t)
+	policy := DefaultPolicy()
+	withRoles := func(roles ...string) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{Subject: "jane", Roles: roles})
+	}
+
+	t.Run("happy path: role grants permission", func(t *testing.T) {
+		as.NoError(policy.Authorize(context.Background(), BooksRead))
+		as.NoError(policy.Authorize(withRoles("editor"), AuthorsUpdate))
+		as.NoError(policy.Authorize(withRoles("admin"), AuthorsDelete))
+	})
+
+	t.Run("input error: missing permission is named", func(t *testing.T) {
+		err := policy.Authorize(withRoles("editor"), BooksDelete)
+		as.True(errors.Is(err, domain.ErrForbidden))
+		as.Contains(err.Error(), BooksDelete)
+
+		err = policy.Authorize(context.Background(), BooksCreate)
+		as.True(errors.Is(err, domain.ErrForbidden))
+	})
+
+	t.Run("credentials without roles get the anonymous role", func(t *testing.T) {
+		as.NoError(policy.Authorize(withRoles(), BooksRead))
+		as.True(errors.Is(policy.Authorize(withRoles(), BooksCreate), domain.ErrForbidden))
+	})
+
+	t.Run("scopes narrow the permissions granted by roles", func(t *testing.T) {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Roles: []string{"admin"}, Scopes: []string{"books:*"}})
+		as.NoError(policy.Authorize(ctx, BooksDelete))
+		as.Error(policy.Authorize(ctx, AuthorsDelete))
+	})
+
+	t.Run("policy file is loaded", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "policy.yaml")
+		as.NoError(os.WriteFile(path, []byte("roles:\n  auditor: [books:read, authors:read]\n"), 0o600))
+		p, err := LoadPolicy(path)
+		as.NoError(err)
+		as.NoError(p.Authorize(withRoles("auditor"), BooksRead))
+		as.Error(p.Authorize(context.Background(), BooksRead))
+	})
+}
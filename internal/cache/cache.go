@@ -0,0 +1,161 @@
+// Package cache provides the store behind the read-through service caches
+// and the keys both catalog caches share, so a write to one entity can
+// invalidate what the other cached about it.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store holds encoded values under string keys. Values are byte slices so
+// that a shared store can implement the interface as well as process memory.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, keys ...string)
+	DeletePrefix(ctx context.Context, prefix string)
+}
+
+// Key namespaces shared by the book and author caches.
+const (
+	BookSearchPrefix   = "books:search:"
+	AuthorSearchPrefix = "authors:search:"
+)
+
+// MaxDepth is the deepest association level of the reads that are cached.
+// Deeper reads embed entities a write cannot trace back to, e.g. the
+// co-authors of a book's authors, so they would outlive its invalidation.
+const MaxDepth = 1
+
+func BookKey(id string) string {
+	return "book:" + id
+}
+
+func AuthorKey(id string) string {
+	return "author:" + id
+}
+
+// Variant keys a narrowed representation of the entry at key, such as a
+// sparse fieldset, so that DeleteVariants drops it along with the entry.
+func Variant(key, variant string) string {
+	if variant == "" {
+		return key
+	}
+	return key + "?" + variant
+}
+
+// DeleteVariants deletes keys and every variant of them.
+func DeleteVariants(ctx context.Context, store Store, keys ...string) {
+	store.Delete(ctx, keys...)
+	for _, key := range keys {
+		store.DeletePrefix(ctx, key+"?")
+	}
+}
+
+func BookSearchKey(filter, filterValue string) string {
+	return BookSearchPrefix + filter + ":" + filterValue
+}
+
+func AuthorSearchKey(filter, filterValue string) string {
+	return AuthorSearchPrefix + filter + ":" + filterValue
+}
+
+type entry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// LRU is an in-process Store bounded by entry count and total value size,
+// evicting the least recently used entries first. Expired entries are
+// dropped when read or evicted.
+type LRU struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+// NewLRU returns an LRU holding at most maxEntries entries and maxBytes bytes
+// of values; a limit of 0 or less is not enforced.
+func NewLRU(maxEntries, maxBytes int) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (l *LRU) Get(ctx context.Context, key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !l.now().Before(e.expires) {
+		l.remove(el)
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (l *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxBytes > 0 && len(value) > l.maxBytes {
+		return
+	}
+	if el, ok := l.entries[key]; ok {
+		l.remove(el)
+	}
+	l.entries[key] = l.order.PushFront(&entry{key: key, value: value, expires: l.now().Add(ttl)})
+	l.bytes += len(value)
+	for (l.maxEntries > 0 && l.order.Len() > l.maxEntries) || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *LRU) Delete(ctx context.Context, keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := l.entries[key]; ok {
+			l.remove(el)
+		}
+	}
+}
+
+func (l *LRU) DeletePrefix(ctx context.Context, prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, el := range l.entries {
+		if strings.HasPrefix(key, prefix) {
+			l.remove(el)
+		}
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet dropped.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRU) remove(el *list.Element) {
+	e := l.order.Remove(el).(*entry)
+	delete(l.entries, e.key)
+	l.bytes -= len(e.value)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	t.Run("happy path: values are returned until they expire", func(t *testing.T) {
+		l := NewLRU(10, 0)
+		l.now = func() time.Time { return now }
+		l.Set(ctx, BookKey("1"), []byte("a"), time.Minute)
+		v, ok := l.Get(ctx, BookKey("1"))
+		as.True(ok)
+		as.Equal([]byte("a"), v)
+
+		l.now = func() time.Time { return now.Add(time.Minute) }
+		_, ok = l.Get(ctx, BookKey("1"))
+		as.False(ok)
+		as.Equal(0, l.Len())
+	})
+
+	t.Run("least recently used entries are evicted past the limits", func(t *testing.T) {
+		l := NewLRU(2, 5)
+		l.Set(ctx, "a", []byte("1"), time.Minute)
+		l.Set(ctx, "b", []byte("2"), time.Minute)
+		l.Get(ctx, "a")
+		l.Set(ctx, "c", []byte("3"), time.Minute)
+		_, ok := l.Get(ctx, "b")
+		as.False(ok)
+		_, ok = l.Get(ctx, "a")
+		as.True(ok)
+
+		l.Set(ctx, "d", []byte("55555"), time.Minute)
+		as.Equal(1, l.Len())
+	})
+
+	t.Run("prefix deletion only removes matching keys", func(t *testing.T) {
+		l := NewLRU(0, 0)
+		l.Set(ctx, BookSearchKey("title", "go"), []byte("x"), time.Minute)
+		l.Set(ctx, AuthorSearchKey("name", "jo"), []byte("y"), time.Minute)
+		l.DeletePrefix(ctx, BookSearchPrefix)
+		_, ok := l.Get(ctx, BookSearchKey("title", "go"))
+		as.False(ok)
+		_, ok = l.Get(ctx, AuthorSearchKey("name", "jo"))
+		as.True(ok)
+	})
+}
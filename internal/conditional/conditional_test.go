@@ -0,0 +1,59 @@
+package conditional
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotModified(t *testing.T) {
+	as := assert.New(t)
+	modified := time.Date(2026, 3, 1, 10, 0, 0, 500, time.UTC)
+	etag, err := ETag("application/json", map[string]string{"title": "About test"})
+	as.NoError(err)
+
+	check := func(header, value string) (bool, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/books/1", nil)
+		if header != "" {
+			c.Request.Header.Set(header, value)
+		}
+		return NotModified(c, etag, modified), w
+	}
+
+	t.Run("happy path: headers set and full response without preconditions", func(t *testing.T) {
+		ok, w := check("", "")
+		as.False(ok)
+		as.Equal(etag, w.Header().Get("ETag"))
+		as.Equal("Sun, 01 Mar 2026 10:00:00 GMT", w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("If-None-Match with the current tag", func(t *testing.T) {
+		ok, _ := check("If-None-Match", `"stale", `+etag)
+		as.True(ok)
+		ok, _ = check("If-None-Match", `"stale"`)
+		as.False(ok)
+	})
+
+	t.Run("If-Modified-Since compares at second precision", func(t *testing.T) {
+		ok, _ := check("If-Modified-Since", "Sun, 01 Mar 2026 10:00:00 GMT")
+		as.True(ok)
+		ok, _ = check("If-Modified-Since", "Sun, 01 Mar 2026 09:59:59 GMT")
+		as.False(ok)
+	})
+
+	t.Run("tag changes with the content", func(t *testing.T) {
+		other, _ := ETag("application/json", map[string]string{"title": "About tests"})
+		as.NotEqual(etag, other)
+	})
+
+	t.Run("tag differs between formats of the same content", func(t *testing.T) {
+		xml, _ := ETag("application/xml", map[string]string{"title": "About test"})
+		as.NotEqual(etag, xml)
+	})
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Int reads an integer from the environment variable key, falling back to def
+// when the variable is unset.
+func Int(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return n, nil
+}
+
+// Float reads a floating point number from the environment variable key,
+// falling back to def when the variable is unset.
+func Float(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return f, nil
+}
+
+// Bool reads a boolean such as "true" or "0" from the environment variable
+// key, falling back to def when the variable is unset.
+func Bool(key string, def bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
+
+// Duration reads a time.Duration such as "5s" from the environment variable
+// key, falling back to def when the variable is unset.
+func Duration(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return d, nil
+}
+
+// RouteDurations reads per-route durations from the environment variable key.
+// Entries are comma separated and take the form "METHOD /route/template=duration",
+// e.g. "GET /api/v1/books/filter=10s,DELETE /api/v1/authors/:id=2s".
+func RouteDurations(key string) (map[string]time.Duration, error) {
+	routes := make(map[string]time.Duration)
+	value := os.Getenv(key)
+	if value == "" {
+		return routes, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%s: entry %q is missing a duration", key, entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(entry[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		routes[strings.Join(strings.Fields(entry[:i]), " ")] = d
+	}
+	return routes, nil
+}
+
+// List reads a comma separated list from the environment variable key,
+// trimming spaces and skipping empty entries. An unset variable is an empty
+// list.
+func List(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteDurations(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: reads every route", func(t *testing.T) {
+		t.Setenv("ROUTE_TIMEOUTS", " GET  /api/v1/books/filter=10s, ,DELETE /api/v1/authors/:id = 2s")
+		routes, err := RouteDurations("ROUTE_TIMEOUTS")
+		as.NoError(err)
+		as.Equal(map[string]time.Duration{
+			"GET /api/v1/books/filter":   10 * time.Second,
+			"DELETE /api/v1/authors/:id": 2 * time.Second,
+		}, routes)
+	})
+
+	t.Run("happy path: unset is no route", func(t *testing.T) {
+		t.Setenv("ROUTE_TIMEOUTS", "")
+		routes, err := RouteDurations("ROUTE_TIMEOUTS")
+		as.NoError(err)
+		as.Empty(routes)
+	})
+
+	t.Run("input error: malformed durations are rejected", func(t *testing.T) {
+		for _, value := range []string{
+			"GET /api/v1/books/filter=10",
+			"GET /api/v1/books/filter=ten seconds",
+			"GET /api/v1/books/filter=",
+			"GET /api/v1/books/filter",
+			"GET /api/v1/books=1s,POST /api/v1/books",
+		} {
+			t.Setenv("ROUTE_TIMEOUTS", value)
+			_, err := RouteDurations("ROUTE_TIMEOUTS")
+			as.ErrorContains(err, "ROUTE_TIMEOUTS", value)
+		}
+	})
+}
+
+func TestDuration(t *testing.T) {
+	as := assert.New(t)
+
+	t.Setenv("REQUEST_TIMEOUT", "")
+	d, err := Duration("REQUEST_TIMEOUT", 5*time.Second)
+	as.NoError(err)
+	as.Equal(5*time.Second, d)
+
+	t.Setenv("REQUEST_TIMEOUT", "250ms")
+	d, err = Duration("REQUEST_TIMEOUT", 5*time.Second)
+	as.NoError(err)
+	as.Equal(250*time.Millisecond, d)
+
+	t.Setenv("REQUEST_TIMEOUT", "5")
+	_, err = Duration("REQUEST_TIMEOUT", 5*time.Second)
+	as.ErrorContains(err, "REQUEST_TIMEOUT")
+}
@@ -0,0 +1,120 @@
+// Package health reports whether the service is alive and ready for traffic.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Checker tracks the state readiness depends on: the database connection,
+// whether migrations ran, maintenance mode and graceful shutdown.
+type Checker struct {
+	db              *sql.DB
+	maintenanceFile string
+	migrated        atomic.Bool
+	maintenance     atomic.Bool
+	shuttingDown    atomic.Bool
+}
+
+// NewChecker returns a Checker pinging db. When maintenanceFile is set, the
+// service is in maintenance mode for as long as that file exists.
+func NewChecker(db *sql.DB, maintenanceFile string) *Checker {
+	return &Checker{db: db, maintenanceFile: maintenanceFile}
+}
+
+// SetMigrated records whether the schema migrations completed.
+func (c *Checker) SetMigrated(ok bool) {
+	c.migrated.Store(ok)
+}
+
+// SetMaintenance switches maintenance mode on or off.
+func (c *Checker) SetMaintenance(on bool) {
+	c.maintenance.Store(on)
+}
+
+// ShutDown makes readiness fail from now on so load balancers stop routing
+// new requests while in-flight ones drain.
+func (c *Checker) ShutDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs every readiness check and returns the result of each, keyed by
+// check name, with every failure joined as the error.
+func (c *Checker) Ready(ctx context.Context) (map[string]string, error) {
+	checks := make(map[string]string)
+	var errs []error
+	fail := func(name string, err error) {
+		checks[name] = err.Error()
+		errs = append(errs, err)
+	}
+
+	if err := c.db.PingContext(ctx); err != nil {
+		fail("database", err)
+	} else {
+		checks["database"] = "ok"
+	}
+	if c.migrated.Load() {
+		checks["migrations"] = "ok"
+	} else {
+		fail("migrations", errors.New("migrations have not completed"))
+	}
+	if c.inMaintenance() {
+		fail("maintenance", errors.New("maintenance mode is on"))
+	} else {
+		checks["maintenance"] = "off"
+	}
+	if c.shuttingDown.Load() {
+		fail("shutdown", errors.New("server is shutting down"))
+	} else {
+		checks["shutdown"] = "no"
+	}
+	return checks, errors.Join(errs...)
+}
+
+func (c *Checker) inMaintenance() bool {
+	if c.maintenance.Load() {
+		return true
+	}
+	if c.maintenanceFile == "" {
+		return false
+	}
+	_, err := os.Stat(c.maintenanceFile)
+	return err == nil
+}
+
+type HealthHandler struct {
+	Checker *Checker
+}
+
+// NewHealthHandler registers /healthz for liveness and /readyz for readiness.
+func NewHealthHandler(router *gin.Engine, checker *Checker) {
+	handler := &HealthHandler{
+		Checker: checker,
+	}
+	router.GET("/healthz", handler.Liveness)
+	router.GET("/readyz", handler.Readiness)
+}
+
+// Liveness reports that the process is up and serving HTTP.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness reports whether the service should receive traffic.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	checks, err := h.Checker.Ready(ctx)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": checks})
+}
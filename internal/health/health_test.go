@@ -0,0 +1,80 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// readyz serves /readyz for a checker whose database pings return pingErr.
+func readyz(t *testing.T, pingErr error, setup func(*Checker)) (int, map[string]interface{}) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectPing().WillReturnError(pingErr)
+
+	checker := NewChecker(db, "")
+	setup(checker)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHealthHandler(router, checker)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, body
+}
+
+func TestReadiness(t *testing.T) {
+	as := assert.New(t)
+
+	t.Run("happy path: ready once migrated", func(t *testing.T) {
+		code, body := readyz(t, nil, func(c *Checker) { c.SetMigrated(true) })
+		as.Equal(http.StatusOK, code)
+		as.Equal("ready", body["status"])
+		as.Equal("ok", body["checks"].(map[string]interface{})["database"])
+	})
+
+	t.Run("database error: unavailable while the database is down", func(t *testing.T) {
+		code, body := readyz(t, errors.New("connection refused"), func(c *Checker) { c.SetMigrated(true) })
+		as.Equal(http.StatusServiceUnavailable, code)
+		as.Equal("unavailable", body["status"])
+		as.Equal("connection refused", body["checks"].(map[string]interface{})["database"])
+	})
+
+	t.Run("server error: unavailable before migrations complete", func(t *testing.T) {
+		code, body := readyz(t, nil, func(c *Checker) {})
+		as.Equal(http.StatusServiceUnavailable, code)
+		as.Equal("migrations have not completed", body["checks"].(map[string]interface{})["migrations"])
+	})
+
+	t.Run("server error: unavailable once shutting down", func(t *testing.T) {
+		code, _ := readyz(t, nil, func(c *Checker) {
+			c.SetMigrated(true)
+			c.ShutDown()
+		})
+		as.Equal(http.StatusServiceUnavailable, code)
+	})
+}
+
+func TestLiveness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHealthHandler(router, NewChecker(nil, ""))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
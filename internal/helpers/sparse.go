@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"encoding/json"
+	"geniuscrew/domain"
+)
+
+// Sparse trims v, a resource or a slice of resources, to the fields opts
+// asks for. v is returned as is when opts does not narrow anything.
+func Sparse(v interface{}, opts domain.QueryOptions, associations map[string]string) (interface{}, error) {
+	if !opts.Sparse() {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var list []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		for _, object := range list {
+			trim(object, opts, associations)
+		}
+		return list, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	trim(object, opts, associations)
+	return object, nil
+}
+
+func trim(object map[string]json.RawMessage, opts domain.QueryOptions, associations map[string]string) {
+	for field := range object {
+		if !opts.Wants(field, associations) {
+			delete(object, field)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"net/http"
+)
+
+// ErrorStatus returns the status code for a service error not handled by the
+// caller. Authorization failures are 403 and an unreachable dependency is 503.
+// Errors caused by the request context ending are reported as 504 when the
+// deadline passed and 503 when the request was cancelled; anything else is a
+// 500.
+func ErrorStatus(ctx context.Context, err error) int {
+	switch {
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,105 @@
+// Package idempotency remembers the responses to requests made with an
+// idempotency key, so that a retried request gets the first response instead
+// of making its change again. Records live behind a Store interface; the
+// MySQL store shares them between instances.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Record is a request made with an idempotency key and, once Done, its
+// response. A record in progress expires after the lock timeout, a done one
+// after the retention, and an expired record is as good as none.
+type Record struct {
+	// ID is the hash of the client and the key.
+	ID string `gorm:"primaryKey;size:64"`
+	// Fingerprint is the hash of the request, so that the key cannot be
+	// reused for another one.
+	Fingerprint string `gorm:"size:64"`
+	Done        bool
+	Status      int
+	ContentType string    `gorm:"size:128"`
+	Body        []byte    `gorm:"type:mediumblob"`
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Hash returns the hex SHA-256 of parts, each followed by a zero byte so
+// that they cannot run into one another.
+func Hash(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Store holds the records.
+type Store interface {
+	// Reserve saves record, a request in progress, unless an unexpired record
+	// has its ID. It returns that record and false, or record and true.
+	Reserve(ctx context.Context, record Record) (Record, bool, error)
+	// Complete saves the response of the request reserved as record.ID.
+	Complete(ctx context.Context, record Record) error
+	// Release forgets the record id, so that the request can be made again.
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryStore keeps records in process memory, which suits a single
+// instance. Expired records are dropped.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// sweepInterval is how often expired records are dropped.
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), now: time.Now}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, record Record) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for id, r := range s.records {
+			if !r.ExpiresAt.After(now) {
+				delete(s.records, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	if existing, ok := s.records[record.ID]; ok && existing.ExpiresAt.After(now) {
+		return existing, false, nil
+	}
+	s.records[record.ID] = record
+	return record, true, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ID] = record
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	return nil
+}
@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	first := Record{ID: Hash([]byte("client"), []byte("key")), Fingerprint: "a", ExpiresAt: now.Add(time.Minute)}
+
+	t.Run("happy path: the first request reserves the key", func(t *testing.T) {
+		r, reserved, err := store.Reserve(ctx, first)
+		as.NoError(err)
+		as.True(reserved)
+		as.Equal(first, r)
+	})
+
+	t.Run("retry: gets the request in progress, then its response", func(t *testing.T) {
+		r, reserved, _ := store.Reserve(ctx, Record{ID: first.ID, Fingerprint: "b", ExpiresAt: now.Add(time.Minute)})
+		as.False(reserved)
+		as.Equal(first, r)
+
+		done := first
+		done.Done, done.Status, done.Body, done.ExpiresAt = true, 201, []byte(`{}`), now.Add(time.Hour)
+		as.NoError(store.Complete(ctx, done))
+		r, reserved, _ = store.Reserve(ctx, first)
+		as.False(reserved)
+		as.Equal(done, r)
+	})
+
+	t.Run("expired: the key can be reserved again", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		second := Record{ID: first.ID, Fingerprint: "c", ExpiresAt: now.Add(time.Minute)}
+		r, reserved, _ := store.Reserve(ctx, second)
+		as.True(reserved)
+		as.Equal(second, r)
+	})
+
+	t.Run("released: the key can be reserved again", func(t *testing.T) {
+		as.NoError(store.Release(ctx, first.ID))
+		_, reserved, _ := store.Reserve(ctx, first)
+		as.True(reserved)
+	})
+
+	t.Run("hash: parts do not run into one another", func(t *testing.T) {
+		as.NotEqual(Hash([]byte("ab"), []byte("c")), Hash([]byte("a"), []byte("bc")))
+	})
+}
@@ -0,0 +1,68 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MySQLStore keeps records in the idempotency_keys table, so that a retry
+// reaching another instance gets the same response. Expired records are
+// deleted by the instances as they go.
+type MySQLStore struct {
+	db        *gorm.DB
+	now       func() time.Time
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewMySQLStore(db *gorm.DB) *MySQLStore {
+	return &MySQLStore{db: db, now: time.Now}
+}
+
+func (s *MySQLStore) Reserve(ctx context.Context, record Record) (Record, bool, error) {
+	now := s.now()
+	db := s.db.WithContext(ctx)
+	if err := s.sweep(db, now); err != nil {
+		return Record{}, false, err
+	}
+	err := db.Where("id = ? AND expires_at <= ?", record.ID, now).Delete(&Record{}).Error
+	if err != nil {
+		return Record{}, false, err
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return Record{}, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return record, true, nil
+	}
+	var existing Record
+	err = db.Where("id = ?", record.ID).Take(&existing).Error
+	return existing, false, err
+}
+
+func (s *MySQLStore) Complete(ctx context.Context, record Record) error {
+	return s.db.WithContext(ctx).Model(&Record{ID: record.ID}).
+		Select("done", "status", "content_type", "body", "expires_at").
+		Updates(&record).Error
+}
+
+func (s *MySQLStore) Release(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&Record{}).Error
+}
+
+// sweep deletes the expired records, at most every sweepInterval.
+func (s *MySQLStore) sweep(db *gorm.DB, now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) <= sweepInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+	return db.Where("expires_at <= ?", now).Delete(&Record{}).Error
+}
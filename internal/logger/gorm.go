@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger writes gorm's query log through slog. Every statement is logged
+// at debug level with its duration and row count, statements slower than
+// slowThreshold at warn and failed statements at error.
+type gormLogger struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+// NewGormLogger adapts logger to gorm's logger interface.
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{logger: logger.With("layer", "repository"), slowThreshold: slowThreshold}
+}
+
+// LogMode is a no-op: the level is controlled by the slog handler.
+func (l *gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.InfoContext(ctx, Redact(fmt.Sprintf(msg, args...)))
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.WarnContext(ctx, Redact(fmt.Sprintf(msg, args...)))
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.ErrorContext(ctx, Redact(fmt.Sprintf(msg, args...)))
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	level := slog.LevelDebug
+	switch {
+	case err != nil && !errors.Is(err, gormlogger.ErrRecordNotFound):
+		level = slog.LevelError
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level = slog.LevelWarn
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", Redact(sql)),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.logger.LogAttrs(ctx, level, "sql query", attrs...)
+}
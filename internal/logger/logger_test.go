@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	as := assert.New(t)
+	t.Run("happy path: request id is attached and email redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&buf, slog.LevelInfo)
+		ctx := WithRequestID(context.Background(), "abc123")
+		log.InfoContext(ctx, "author created", "email", "jane@example.com")
+
+		var record map[string]interface{}
+		as.NoError(json.Unmarshal(buf.Bytes(), &record))
+		as.Equal("abc123", record["request_id"])
+		as.Equal(Redacted, record["email"])
+	})
+
+	t.Run("records below the configured level are dropped", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := New(&buf, slog.LevelWarn)
+		log.Info("ignored")
+		as.Equal(0, buf.Len())
+	})
+
+	t.Run("emails inside sql statements are masked", func(t *testing.T) {
+		as.Equal("SELECT * FROM `authors` WHERE email = '"+Redacted+"'", Redact("SELECT * FROM `authors` WHERE email = 'jane@example.com'"))
+	})
+}
@@ -0,0 +1,139 @@
+// Package metrics defines the Prometheus collectors exposed at /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector served by Handler.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+// HTTP instrumentation, labelled by route template rather than raw path so
+// that IDs do not create new series.
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+
+	HTTPRequestTimeouts = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_request_timeouts_total",
+		Help: "HTTP requests that ran past their deadline, by method and route template.",
+	}, []string{"method", "route"})
+)
+
+// gRPC instrumentation, labelled by full method name and status code.
+var (
+	GRPCRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	GRPCRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "gRPC call latency by method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// Database instrumentation, recorded through gorm callbacks.
+var (
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database statement latency by table and operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+
+	DBQueryErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Failed database statements by table and operation.",
+	}, []string{"table", "operation"})
+)
+
+// Catalog counters, incremented by the services after a successful write.
+var (
+	BooksCreated = factory.NewCounter(prometheus.CounterOpts{
+		Name: "catalog_books_created_total",
+		Help: "Books created.",
+	})
+
+	AuthorsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Name: "catalog_authors_created_total",
+		Help: "Authors created.",
+	})
+
+	AuthorLinksChanged = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "catalog_author_links_changed_total",
+		Help: "Changes to the books linked to an author, by operation (create, update, delete).",
+	}, []string{"operation"})
+)
+
+// Webhook deliveries, labelled by event type and outcome (succeeded, retried,
+// failed).
+var WebhookDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+	Name: "webhook_deliveries_total",
+	Help: "Webhook delivery attempts by event type and outcome.",
+}, []string{"event", "outcome"})
+
+// Outbox events handed to the sinks, labelled by event type and outcome (sent,
+// retried).
+var OutboxEvents = factory.NewCounterVec(prometheus.CounterOpts{
+	Name: "outbox_events_total",
+	Help: "Outbox dispatch attempts by event type and outcome.",
+}, []string{"event", "outcome"})
+
+// Requests made with an Idempotency-Key, labelled by outcome (stored,
+// replayed, in_progress, mismatch).
+var IdempotentRequests = factory.NewCounterVec(prometheus.CounterOpts{
+	Name: "idempotent_requests_total",
+	Help: "Requests made with an Idempotency-Key by outcome.",
+}, []string{"outcome"})
+
+// Read-through cache effectiveness, labelled by cache (books, authors).
+var (
+	CacheHits = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "Service reads answered from the cache.",
+	}, []string{"cache"})
+
+	CacheMisses = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "Service reads that went to the database.",
+	}, []string{"cache"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geniuscrew/internal/dbtest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	as := assert.New(t)
+	BooksCreated.Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	as.Equal(http.StatusOK, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	as.Contains(string(body), "catalog_books_created_total")
+	as.Contains(string(body), "go_goroutines")
+}
+
+func TestGormPlugin(t *testing.T) {
+	as := assert.New(t)
+	db, sql := dbtest.New(t)
+	as.NoError(db.Use(GormPlugin{}))
+	errors := DBQueryErrors.WithLabelValues("books", "query")
+
+	t.Run("happy path: statements are timed by table and operation", func(t *testing.T) {
+		sql.ExpectQuery("SELECT \\* FROM `books`").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		before := samples(t, "books", "query")
+		failures := testutil.ToFloat64(errors)
+
+		var rows []struct{ ID int }
+		as.NoError(db.Table("books").Find(&rows).Error)
+		as.Equal(before+1, samples(t, "books", "query"))
+		as.Equal(failures, testutil.ToFloat64(errors))
+	})
+
+	t.Run("database error: failed statements are counted", func(t *testing.T) {
+		sql.ExpectQuery("SELECT \\* FROM `books`").WillReturnError(io.ErrUnexpectedEOF)
+		failures := testutil.ToFloat64(errors)
+
+		var rows []struct{ ID int }
+		as.Error(db.Table("books").Find(&rows).Error)
+		as.Equal(failures+1, testutil.ToFloat64(errors))
+	})
+}
+
+// samples returns the number of statements DBQueryDuration has timed for
+// table and operation.
+func samples(t *testing.T, table, operation string) uint64 {
+	var m dto.Metric
+	if err := DBQueryDuration.WithLabelValues(table, operation).(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticate stores the caller's identity in the request context. Requests
+// with invalid credentials are rejected with 401, as are unauthenticated
+// requests using a method other than GET, HEAD or OPTIONS, except to the
+// "METHOD /route/template" keys listed in open, whose handlers decide what
+// anonymous callers may do.
+func Authenticate(a *auth.Authenticator, open ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		principal, ok, err := a.Authenticate(c.Request)
+		if err != nil {
+			if errors.Is(err, domain.ErrUnauthorized) {
+				unauthorized(c, err.Error())
+				return
+			}
+			c.AbortWithStatusJSON(helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+		if !ok {
+			if !helpers.In(c.Request.Method, http.MethodGet, http.MethodHead, http.MethodOptions) && !helpers.In(c.Request.Method+" "+c.FullPath(), open...) {
+				unauthorized(c, "authentication required")
+				return
+			}
+			c.Next()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="geniuscrew"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
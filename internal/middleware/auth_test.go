@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geniuscrew/domain/mocks/repository"
+	"geniuscrew/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{})
+	as.NoError(err)
+	router := gin.New()
+	router.Use(Authenticate(auth.NewAuthenticator(verifier, &repository.APIKeyRepositoryMock{}), "POST /graphql"))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/api/v1/books/:id", ok)
+	router.POST("/api/v1/books", ok)
+	router.POST("/graphql", ok)
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/api/v1/books/1", http.StatusNoContent},
+		{http.MethodPost, "/api/v1/books", http.StatusUnauthorized},
+		{http.MethodPost, "/graphql", http.StatusNoContent},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		as.Equal(tc.status, w.Code, tc.method+" "+tc.path)
+	}
+}
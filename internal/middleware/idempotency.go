@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"geniuscrew/internal/idempotency"
+	"geniuscrew/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from the first
+	// request made with the key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKey bounds the length of an Idempotency-Key.
+const maxIdempotencyKey = 255
+
+// Idempotency makes POST requests carrying an Idempotency-Key safe to retry.
+// The first response to a key of a client, other than a 5xx, is kept for ttl
+// and replayed to the requests repeating it. The key is held for at most lock
+// while its first request runs: a concurrent request gets 409 and one with a
+// different method, path, Content-Type, Accept or body 422, as the response
+// replayed to it would be for another request. Store failures let requests
+// through.
+func Idempotency(store idempotency.Store, ttl, lock time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "reading the request body: " + err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		record := idempotency.Record{
+			ID:          idempotency.Hash([]byte(clientKey(c)), []byte(key)),
+			Fingerprint: idempotency.Hash(
+				[]byte(c.Request.Method), []byte(c.Request.URL.RequestURI()),
+				[]byte(c.GetHeader("Content-Type")), []byte(c.GetHeader("Accept")), body,
+			),
+			ExpiresAt:   time.Now().Add(lock),
+		}
+		existing, reserved, err := store.Reserve(ctx, record)
+		if err != nil {
+			slog.WarnContext(ctx, "idempotency store failed", "error", err)
+			c.Next()
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				metrics.IdempotentRequests.WithLabelValues("mismatch").Inc()
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was used for a different request"})
+			case !existing.Done:
+				metrics.IdempotentRequests.WithLabelValues("in_progress").Inc()
+				c.Header("Retry-After", "1")
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is in progress"})
+			default:
+				metrics.IdempotentRequests.WithLabelValues("replayed").Inc()
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// the response is kept even when the client has gone
+		ctx = context.WithoutCancel(ctx)
+		if w.Status() >= http.StatusInternalServerError {
+			if err := store.Release(ctx, record.ID); err != nil {
+				slog.WarnContext(ctx, "idempotency store failed", "error", err)
+			}
+			return
+		}
+		record.Done = true
+		record.Status = w.Status()
+		record.ContentType = w.Header().Get("Content-Type")
+		record.Body = w.body.Bytes()
+		record.ExpiresAt = time.Now().Add(ttl)
+		if err := store.Complete(ctx, record); err != nil {
+			slog.WarnContext(ctx, "idempotency store failed", "error", err)
+			return
+		}
+		metrics.IdempotentRequests.WithLabelValues("stored").Inc()
+	}
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"geniuscrew/internal/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	created := 0
+	started, release := make(chan struct{}), make(chan struct{})
+	router := gin.New()
+	router.Use(Idempotency(idempotency.NewMemoryStore(), time.Hour, time.Minute))
+	router.POST("/authors", func(c *gin.Context) {
+		if c.Query("wait") != "" {
+			close(started)
+			<-release
+		}
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "unavailable"})
+			return
+		}
+		created++
+		c.JSON(http.StatusCreated, gin.H{"id": created})
+	})
+	post := func(target, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("happy path: a retry replays the first response", func(t *testing.T) {
+		w := post("/authors", "k1", `{"name":"Frank"}`)
+		as.Equal(http.StatusCreated, w.Code)
+		as.Empty(w.Header().Get(IdempotentReplayedHeader))
+		w = post("/authors", "k1", `{"name":"Frank"}`)
+		as.Equal(http.StatusCreated, w.Code)
+		as.Equal("true", w.Header().Get(IdempotentReplayedHeader))
+		as.Equal(`{"id":1}`, w.Body.String())
+		as.Equal(1, created)
+	})
+
+	t.Run("happy path: requests without a key are not remembered", func(t *testing.T) {
+		post("/authors", "", `{"name":"Frank"}`)
+		post("/authors", "", `{"name":"Frank"}`)
+		as.Equal(3, created)
+	})
+
+	t.Run("input error: the key reused for another request", func(t *testing.T) {
+		w := post("/authors", "k1", `{"name":"Brian"}`)
+		as.Equal(http.StatusUnprocessableEntity, w.Code)
+		as.Equal(3, created)
+	})
+
+	t.Run("input error: the key reused with another Accept", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/authors", strings.NewReader(`{"name":"Frank"}`))
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		req.Header.Set("Accept", "application/xml")
+		router.ServeHTTP(w, req)
+		as.Equal(http.StatusUnprocessableEntity, w.Code)
+		as.Equal(3, created)
+	})
+
+	t.Run("conflict: the first request is still running", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- post("/authors?wait=1", "k2", `{}`) }()
+		<-started
+		w := post("/authors?wait=1", "k2", `{}`)
+		as.Equal(http.StatusConflict, w.Code)
+		as.Equal("1", w.Header().Get("Retry-After"))
+		close(release)
+		as.Equal(http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("server error: the key is released for the retry", func(t *testing.T) {
+		as.Equal(http.StatusServiceUnavailable, post("/authors?fail=1", "k3", `{}`).Code)
+		as.Equal(http.StatusServiceUnavailable, post("/authors?fail=1", "k3", `{}`).Code)
+		w := post("/authors?fail=1", "k3", `{}`)
+		as.Empty(w.Header().Get(IdempotentReplayedHeader))
+	})
+}
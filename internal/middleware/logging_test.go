@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geniuscrew/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDAccessLog(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logger.New(&buf, slog.LevelInfo)))
+	router.GET("/books/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	// get serves a request with the given X-Request-ID and returns the
+	// response and the access log record written for it.
+	get := func(id string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/books/7", nil)
+		if id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		return w, record
+	}
+
+	t.Run("happy path: the caller's id is echoed and logged", func(t *testing.T) {
+		w, record := get("req-42")
+		as.Equal("req-42", w.Header().Get(RequestIDHeader))
+		as.Equal("req-42", record["request_id"])
+		as.Equal("/books/:id", record["route"])
+		as.Equal(float64(http.StatusNotFound), record["status"])
+		as.Equal("WARN", record["level"])
+	})
+
+	t.Run("happy path: a missing id is generated", func(t *testing.T) {
+		w, record := get("")
+		id := w.Header().Get(RequestIDHeader)
+		as.Len(id, 32)
+		as.Equal(id, record["request_id"])
+	})
+
+	t.Run("input error: a malformed id is replaced", func(t *testing.T) {
+		w, record := get("bad id")
+		id := w.Header().Get(RequestIDHeader)
+		as.NotEqual("bad id", id)
+		as.Len(id, 32)
+		as.Equal(id, record["request_id"])
+	})
+}
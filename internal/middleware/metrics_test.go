@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geniuscrew/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	var inFlight float64
+	router.GET("/api/v1/books/:id", func(c *gin.Context) {
+		inFlight = testutil.ToFloat64(metrics.HTTPRequestsInFlight)
+		c.Status(http.StatusOK)
+	})
+	get := func(path string) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	t.Run("happy path: requests are counted by route template", func(t *testing.T) {
+		requests := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/v1/books/:id", "200")
+		before := testutil.ToFloat64(requests)
+		get("/api/v1/books/1")
+		get("/api/v1/books/2")
+		as.Equal(before+2, testutil.ToFloat64(requests))
+		// the request was in flight while served, and no longer is
+		as.Equal(float64(1), inFlight)
+		as.Zero(testutil.ToFloat64(metrics.HTTPRequestsInFlight))
+	})
+
+	t.Run("input error: unknown paths share one label", func(t *testing.T) {
+		requests := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")
+		before := testutil.ToFloat64(requests)
+		get("/nope/1")
+		get("/nope/2")
+		as.Equal(before+2, testutil.ToFloat64(requests))
+	})
+}
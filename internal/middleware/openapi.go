@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateRequests rejects requests whose parameters or body do not match the
+// operation doc describes with 422, or with 400 when the body cannot be
+// parsed at all, as the handlers answer. Requests to routes missing from doc pass
+// through untouched, as do credentials: Authenticate checks those.
+func ValidateRequests(doc *openapi3.T) (gin.HandlerFunc, error) {
+	// Match on path alone, whatever host the API is reached on.
+	routed := *doc
+	routed.Servers = nil
+	router, err := gorillamux.NewRouter(&routed)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	// Only JSON bodies are checked against the schemas; XML and MessagePack
+	// bodies are left to the handlers' validation.
+	skipBody := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc, ExcludeRequestBody: true}
+	return func(c *gin.Context) {
+		route, params, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+		opts := options
+		if c.ContentType() != "" && c.ContentType() != gin.MIMEJSON {
+			opts = skipBody
+		}
+		err = openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: params,
+			Route:      route,
+			Options:    opts,
+		})
+		if err != nil {
+			status := http.StatusUnprocessableEntity
+			var parseErr *openapi3filter.ParseError
+			if errors.As(err, &parseErr) {
+				status = http.StatusBadRequest
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": validationMessage(err)})
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// validationMessage keeps the reason and location of a validation failure
+// and drops the schema dump kin-openapi appends.
+func validationMessage(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		message := schemaErr.Reason
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			message = "/" + strings.Join(pointer, "/") + ": " + message
+		}
+		var reqErr *openapi3filter.RequestError
+		if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+			message = "parameter " + reqErr.Parameter.Name + ": " + message
+		}
+		return message
+	}
+	var routeErr *routers.RouteError
+	if errors.As(err, &routeErr) {
+		return routeErr.Reason
+	}
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) {
+		if reqErr.Parameter != nil {
+			return "parameter " + reqErr.Parameter.Name + ": " + reqErr.Error()
+		}
+		return reqErr.Error()
+	}
+	return err.Error()
+}
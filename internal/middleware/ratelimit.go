@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit classes. Search routes preload every association and get their
+// own, usually tighter, limit; GraphQL queries share it.
+const (
+	RateLimitRead   = "read"
+	RateLimitWrite  = "write"
+	RateLimitSearch = "search"
+	// RateLimitAuth limits requests carrying credentials per client IP,
+	// before the credentials are checked.
+	RateLimitAuth = "auth"
+)
+
+// RateLimit applies the limit of the request's class to a bucket per client:
+// the API key when one was used, the JWT subject, or else the client IP.
+// Requests over the limit get 429 with Retry-After. Every limited response
+// carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset. Classes
+// without a limit are not limited, and store failures let requests through.
+func RateLimit(store ratelimit.Store, limits map[string]ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		class := rateLimitClass(c)
+		limit, ok := limits[class]
+		if !ok || limit.Burst <= 0 {
+			c.Next()
+			return
+		}
+		if take(c, store, class+":"+clientKey(c), limit) {
+			c.Next()
+		}
+	}
+}
+
+// RateLimitCredentials limits the requests carrying an API key or a bearer
+// token per client IP, ahead of Authenticate, so that guessing credentials,
+// each guess costing a database lookup, is throttled before any is checked.
+// RateLimit then limits the authenticated client by its identity. A burst
+// of 0 disables it.
+func RateLimitCredentials(store ratelimit.Store, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit.Burst <= 0 || (c.GetHeader("Authorization") == "" && c.GetHeader(auth.APIKeyHeader) == "") {
+			c.Next()
+			return
+		}
+		if take(c, store, RateLimitAuth+":ip:"+c.ClientIP(), limit) {
+			c.Next()
+		}
+	}
+}
+
+// take takes a token from the bucket at key, setting the rate limit headers,
+// and reports whether the request may go on; otherwise it has been answered
+// with 429. Store failures let requests through.
+func take(c *gin.Context, store ratelimit.Store, key string, limit ratelimit.Limit) bool {
+	ctx := c.Request.Context()
+	result, err := store.Take(ctx, key, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+		return true
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+	if !result.Allowed {
+		c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+		return false
+	}
+	return true
+}
+
+func rateLimitClass(c *gin.Context) string {
+	switch {
+	case strings.HasSuffix(c.FullPath(), "/filter") || c.FullPath() == "/graphql":
+		return RateLimitSearch
+	case c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions:
+		return RateLimitRead
+	default:
+		return RateLimitWrite
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	if p, ok := auth.FromContext(c.Request.Context()); ok {
+		if p.KeyID != "" {
+			return "key:" + p.KeyID
+		}
+		return "sub:" + p.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
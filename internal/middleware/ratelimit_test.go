@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geniuscrew/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	newRouter := func(trustedProxies []string) *gin.Engine {
+		router := gin.New()
+		as.NoError(router.SetTrustedProxies(trustedProxies))
+		router.Use(RateLimit(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{RateLimitSearch: {Rate: 0.001, Burst: 1}}))
+		router.GET("/api/v1/books/filter", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+	get := func(router *gin.Engine, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/books/filter", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("input error: a spoofed X-Forwarded-For does not get a fresh bucket", func(t *testing.T) {
+		router := newRouter(nil)
+		as.Equal(http.StatusOK, get(router, "203.0.113.7:1234", "198.51.100.1").Code)
+		w := get(router, "203.0.113.7:1234", "198.51.100.2")
+		as.Equal(http.StatusTooManyRequests, w.Code)
+		as.NotEmpty(w.Header().Get("Retry-After"))
+	})
+
+	t.Run("happy path: clients behind a trusted proxy get a bucket each", func(t *testing.T) {
+		router := newRouter([]string{"10.0.0.0/8"})
+		as.Equal(http.StatusOK, get(router, "10.0.0.1:1234", "198.51.100.1").Code)
+		as.Equal(http.StatusOK, get(router, "10.0.0.1:1234", "198.51.100.2").Code)
+		as.Equal(http.StatusTooManyRequests, get(router, "10.0.0.1:1234", "198.51.100.1").Code)
+	})
+}
+
+func TestRateLimitCredentials(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitCredentials(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 2}))
+	// stands in for Authenticate rejecting every key
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	})
+	router.GET("/api/v1/books", func(c *gin.Context) { c.Status(http.StatusOK) })
+	get := func(remoteAddr, key string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("input error: guessing keys from one IP is throttled before they are checked", func(t *testing.T) {
+		as.Equal(http.StatusUnauthorized, get("203.0.113.7:1234", "gc_guess1"))
+		as.Equal(http.StatusUnauthorized, get("203.0.113.7:1234", "gc_guess2"))
+		as.Equal(http.StatusTooManyRequests, get("203.0.113.7:1234", "gc_guess3"))
+		// another IP has its own bucket
+		as.Equal(http.StatusUnauthorized, get("203.0.113.8:1234", "gc_guess4"))
+	})
+
+	t.Run("happy path: requests without credentials are not counted", func(t *testing.T) {
+		as.Equal(http.StatusOK, get("203.0.113.7:1234", ""))
+	})
+}
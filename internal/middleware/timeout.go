@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"geniuscrew/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout derives the request context from the incoming request with a
+// deadline. routes overrides def for specific "METHOD /route/template" keys;
+// a zero or negative duration leaves the route without a deadline.
+func Timeout(def time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			d = def
+		}
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusGatewayTimeout || ctx.Err() == context.DeadlineExceeded {
+			metrics.HTTPRequestTimeouts.WithLabelValues(c.Request.Method, c.FullPath()).Inc()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(20*time.Millisecond, map[string]time.Duration{
+		"GET /slow/reports": time.Minute,
+		"GET /slow/exports": 0,
+	}))
+	// slow waits for its request to be cancelled, or answers after 100ms
+	slow := func(c *gin.Context) {
+		ctx := c.Request.Context()
+		select {
+		case <-ctx.Done():
+			c.JSON(helpers.ErrorStatus(ctx, ctx.Err()), gin.H{"error": ctx.Err().Error()})
+		case <-time.After(100 * time.Millisecond):
+			c.Status(http.StatusOK)
+		}
+	}
+	router.GET("/slow/books", slow)
+	router.GET("/slow/reports", slow)
+	router.GET("/slow/exports", slow)
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	t.Run("timeout: a slow handler gets 504 after the default timeout", func(t *testing.T) {
+		timeouts := metrics.HTTPRequestTimeouts.WithLabelValues(http.MethodGet, "/slow/books")
+		before := testutil.ToFloat64(timeouts)
+		start := time.Now()
+		as.Equal(http.StatusGatewayTimeout, get("/slow/books"))
+		as.Less(time.Since(start), 100*time.Millisecond)
+		as.Equal(before+1, testutil.ToFloat64(timeouts))
+	})
+
+	t.Run("happy path: a route with a longer timeout finishes", func(t *testing.T) {
+		as.Equal(http.StatusOK, get("/slow/reports"))
+	})
+
+	t.Run("happy path: a zero route timeout sets no deadline", func(t *testing.T) {
+		as.Equal(http.StatusOK, get("/slow/exports"))
+	})
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	router := gin.New()
+	router.Use(Tracing())
+	var handled trace.SpanContext
+	router.GET("/api/v1/books/:id", func(c *gin.Context) {
+		handled = trace.SpanContextFromContext(c.Request.Context())
+		if c.Param("id") == "0" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	get := func(path, traceparent string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if traceparent != "" {
+			req.Header.Set("traceparent", traceparent)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("happy path: continues the caller's trace and returns it", func(t *testing.T) {
+		exporter.Reset()
+		w := get("/api/v1/books/1", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		spans := exporter.GetSpans()
+		as.Len(spans, 1)
+		span := spans[0]
+		as.Equal("GET /api/v1/books/:id", span.Name)
+		as.Equal(trace.SpanKindServer, span.SpanKind)
+		as.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+		as.Equal("00f067aa0ba902b7", span.Parent.SpanID().String())
+		// the handler runs in the span, whose context is sent back
+		as.Equal(span.SpanContext.SpanID(), handled.SpanID())
+		as.Contains(w.Header().Get("traceparent"), span.SpanContext.SpanID().String())
+		as.Equal(codes.Unset, span.Status.Code)
+	})
+
+	t.Run("happy path: starts a trace without a traceparent", func(t *testing.T) {
+		exporter.Reset()
+		get("/api/v1/books/1", "")
+
+		spans := exporter.GetSpans()
+		as.Len(spans, 1)
+		as.True(spans[0].SpanContext.TraceID().IsValid())
+		as.False(spans[0].Parent.IsValid())
+	})
+
+	t.Run("server error: the span is failed", func(t *testing.T) {
+		exporter.Reset()
+		get("/api/v1/books/0", "")
+
+		spans := exporter.GetSpans()
+		as.Len(spans, 1)
+		as.Equal(codes.Error, spans[0].Status.Code)
+	})
+}
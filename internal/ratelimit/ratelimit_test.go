@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	as := assert.New(t)
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	t.Run("happy path: burst is allowed", func(t *testing.T) {
+		r, err := store.Take(context.Background(), "client", limit)
+		as.NoError(err)
+		as.True(r.Allowed)
+		as.Equal(1, r.Remaining)
+		r, _ = store.Take(context.Background(), "client", limit)
+		as.True(r.Allowed)
+		as.Equal(0, r.Remaining)
+		as.Equal(2*time.Second, r.Reset)
+	})
+
+	t.Run("input error: empty bucket is refused with retry after", func(t *testing.T) {
+		r, _ := store.Take(context.Background(), "client", limit)
+		as.False(r.Allowed)
+		as.Equal(time.Second, r.RetryAfter)
+	})
+
+	t.Run("tokens refill over time and buckets are independent", func(t *testing.T) {
+		now = now.Add(1500 * time.Millisecond)
+		r, _ := store.Take(context.Background(), "client", limit)
+		as.True(r.Allowed)
+		r, _ = store.Take(context.Background(), "other", limit)
+		as.True(r.Allowed)
+		as.Equal(1, r.Remaining)
+	})
+}
@@ -0,0 +1,387 @@
+// Package render picks the representation of responses from the Accept
+// header and decodes request bodies by their Content-Type. JSON is the
+// default; XML, MessagePack and, for lists, CSV are also offered.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	MIMEJSON    = "application/json"
+	MIMEXML     = "application/xml"
+	MIMECSV     = "text/csv"
+	MIMEMsgPack = "application/msgpack"
+)
+
+var (
+	ErrNotAcceptable        = errors.New("none of the accepted media types can be produced")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// aliases maps alternative names of a media type to the one used here.
+var aliases = map[string]string{
+	"text/xml":                MIMEXML,
+	"application/x-msgpack":   MIMEMsgPack,
+	"application/vnd.msgpack": MIMEMsgPack,
+}
+
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := new(codec.MsgpackHandle)
+	// Use the str and bin types of the current spec and decode str as string.
+	h.WriteExt = true
+	h.RawToString = true
+	return h
+}()
+
+// Respond writes v with status in the best format the Accept header allows.
+// v is a gin.H envelope or anything that encodes to JSON. CSV is offered only
+// when the payload is a list. When nothing acceptable can be produced it
+// answers 406, except for error responses, which fall back to JSON.
+func Respond(c *gin.Context, status int, v interface{}) {
+	format := Format(c, v)
+	if format == "" {
+		if status >= http.StatusBadRequest {
+			format = MIMEJSON
+		} else {
+			NotAcceptable(c, v)
+			return
+		}
+	}
+	if format == MIMEJSON {
+		c.JSON(status, v)
+		return
+	}
+	data, err := plain(v)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var buf bytes.Buffer
+	switch format {
+	case MIMEXML:
+		buf.WriteString(xml.Header)
+		err = writeXML(&buf, "response", data)
+	case MIMEMsgPack:
+		err = codec.NewEncoder(&buf, msgpackHandle).Encode(data)
+	case MIMECSV:
+		err = writeCSV(&buf, rows(data))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(status, format+"; charset=utf-8", buf.Bytes())
+}
+
+// Format returns the media type Respond writes v in, or "" when the Accept
+// header allows none. It sets Vary: Accept, as the choice depends on it, so
+// that responses written without Respond, e.g. 304, carry it too.
+func Format(c *gin.Context, v interface{}) string {
+	c.Header("Vary", "Accept")
+	return Negotiate(c.GetHeader("Accept"), offers(v)...)
+}
+
+// NotAcceptable answers 406 with the media types v could be written in.
+func NotAcceptable(c *gin.Context, v interface{}) {
+	c.JSON(http.StatusNotAcceptable, gin.H{"error": ErrNotAcceptable.Error(), "available": offers(v)})
+}
+
+func offers(v interface{}) []string {
+	offers := []string{MIMEJSON, MIMEXML, MIMEMsgPack}
+	if isList(v) {
+		offers = append(offers, MIMECSV)
+	}
+	return offers
+}
+
+// Negotiate returns the offer the Accept header prefers, or "" when none is
+// acceptable. Each offer takes the quality value of the most specific range
+// matching it, so "*/*, application/xml;q=0" excludes XML; ties go to the
+// range listed first, then to the earlier offer. An empty header accepts the
+// first offer.
+func Negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	type acceptRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if alias, ok := aliases[mediaType]; ok {
+			mediaType = alias
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	best, bestQ, bestIndex := "", 0.0, 0
+	for _, offer := range offers {
+		index, specificity := -1, -1
+		for i, r := range ranges {
+			if s := matchMediaType(r.mediaType, offer); s > specificity {
+				index, specificity = i, s
+			}
+		}
+		if index < 0 {
+			continue
+		}
+		q := ranges[index].q
+		if q > bestQ || (q == bestQ && q > 0 && index < bestIndex) {
+			best, bestQ, bestIndex = offer, q, index
+		}
+	}
+	return best
+}
+
+// matchMediaType returns how specifically the accepted range matches offer:
+// 2 for the same type, 1 for type/* and 0 for */*, or -1 when it does not.
+func matchMediaType(accepted, offer string) int {
+	switch {
+	case accepted == offer:
+		return 2
+	case strings.HasSuffix(accepted, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(accepted, "*")):
+		return 1
+	case accepted == "*/*":
+		return 0
+	}
+	return -1
+}
+
+// Bind decodes the request body into v according to its Content-Type and
+// validates it like gin's binders do. A missing Content-Type is read as JSON.
+// It returns ErrUnsupportedMediaType for any other format.
+func Bind(c *gin.Context, v interface{}) error {
+	contentType := c.GetHeader("Content-Type")
+	if contentType == "" {
+		return c.ShouldBindJSON(v)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+	if alias, ok := aliases[mediaType]; ok {
+		mediaType = alias
+	}
+	switch mediaType {
+	case MIMEJSON:
+		return c.ShouldBindJSON(v)
+	case MIMEXML:
+		return c.ShouldBindXML(v)
+	case MIMEMsgPack:
+		if err := codec.NewDecoder(c.Request.Body, msgpackHandle).Decode(v); err != nil {
+			return err
+		}
+		if binding.Validator == nil {
+			return nil
+		}
+		return binding.Validator.ValidateStruct(v)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
+
+// BindStatus returns the status code for an error returned by Bind.
+func BindStatus(err error) int {
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+// plain converts v to the maps, slices and scalars its JSON encoding
+// describes, so every format renders the same fields under the same names.
+func plain(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var out interface{}
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+	return numbers(out), nil
+}
+
+// numbers replaces json.Number with int64 or float64.
+func numbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = numbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = numbers(value)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// isList reports whether v, or the payload field of a gin.H envelope, is a
+// list.
+func isList(v interface{}) bool {
+	if envelope, ok := v.(gin.H); ok {
+		v = envelope["payload"]
+	}
+	if v == nil {
+		return false
+	}
+	kind := reflect.ValueOf(v).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// rows returns the entries of a plain list payload.
+func rows(v interface{}) []interface{} {
+	if envelope, ok := v.(map[string]interface{}); ok {
+		v = envelope["payload"]
+	}
+	list, _ := v.([]interface{})
+	return list
+}
+
+// writeXML writes v as element name. Object fields become child elements in
+// key order and list entries become item elements.
+func writeXML(w io.Writer, name string, v interface{}) error {
+	encoder := xml.NewEncoder(w)
+	if err := encodeXML(encoder, name, v); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+func encodeXML(e *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeXML(e, key, v[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXML(e, "item", item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := e.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// writeCSV writes one row per entry of rows under a header of their sorted
+// field names, id first. Nested lists, such as the authors of a book, are
+// flattened into one cell with entries separated by "; ".
+func writeCSV(w io.Writer, rows []interface{}) error {
+	columns := map[string]bool{}
+	for _, row := range rows {
+		if object, ok := row.(map[string]interface{}); ok {
+			for key := range object {
+				columns[key] = true
+			}
+		}
+	}
+	header := make([]string, 0, len(columns))
+	for key := range columns {
+		header = append(header, key)
+	}
+	sort.Slice(header, func(i, j int) bool {
+		if header[i] == "id" || header[j] == "id" {
+			return header[i] == "id"
+		}
+		return header[i] < header[j]
+	})
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		object, _ := row.(map[string]interface{})
+		record := make([]string, len(header))
+		for i, key := range header {
+			record[i] = cell(object[key])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// cell renders a value as one CSV field. A nested object is summarized by its
+// fields other than id, in key order, joined with spaces.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, cell(item))
+		}
+		return strings.Join(parts, "; ")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if key != "id" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if s := cell(v[key]); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return fmt.Sprint(v)
+}
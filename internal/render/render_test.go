@@ -0,0 +1,157 @@
+package render
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+type summary struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+}
+
+type book struct {
+	ID      int       `json:"id"`
+	Title   string    `json:"title"`
+	Authors []summary `json:"authors"`
+}
+
+type request struct {
+	Title string   `json:"title" xml:"title"`
+	Books []string `json:"books" xml:"books>item"`
+}
+
+func respond(accept string, status int, v interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept", accept)
+	Respond(c, status, v)
+	return rec
+}
+
+func TestNegotiate(t *testing.T) {
+	as := assert.New(t)
+	offers := []string{MIMEJSON, MIMEXML, MIMEMsgPack}
+	as.Equal(MIMEJSON, Negotiate("", offers...))
+	as.Equal(MIMEJSON, Negotiate("*/*", offers...))
+	as.Equal(MIMEXML, Negotiate("text/xml", offers...))
+	as.Equal(MIMEXML, Negotiate("application/json;q=0.5, application/xml", offers...))
+	as.Equal(MIMEMsgPack, Negotiate("application/x-msgpack", offers...))
+	as.Equal(MIMEJSON, Negotiate("application/*", offers...))
+	as.Equal("", Negotiate("text/csv", offers...))
+	as.Equal("", Negotiate("application/json;q=0", offers...))
+	as.Equal(MIMEXML, Negotiate("*/*, application/json;q=0", offers...))
+	as.Equal(MIMEMsgPack, Negotiate("application/*;q=0.2, application/msgpack;q=0.8, */*;q=0.5", offers...))
+	as.Equal("", Negotiate("*/*, application/*;q=0", offers...))
+	as.Equal(MIMEXML, Negotiate("application/xml;q=0.5, application/json;q=0.5", offers...))
+}
+
+func TestFormat(t *testing.T) {
+	as := assert.New(t)
+	gin.SetMode(gin.TestMode)
+	format := func(accept string, v interface{}) (string, http.Header) {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept", accept)
+		return Format(c, v), rec.Header()
+	}
+
+	f, header := format("application/msgpack", gin.H{"payload": book{ID: 1}})
+	as.Equal(MIMEMsgPack, f)
+	as.Equal("Accept", header.Get("Vary"))
+	f, _ = format("text/csv", gin.H{"payload": book{ID: 1}})
+	as.Empty(f)
+	f, _ = format("text/csv", gin.H{"payload": []book{{ID: 1}}})
+	as.Equal(MIMECSV, f)
+}
+
+func TestRespond(t *testing.T) {
+	as := assert.New(t)
+	books := []book{
+		{ID: 1, Title: "Go", Authors: []summary{{ID: 7, Name: "John", Surname: "Doe"}, {ID: 8, Name: "Jane", Surname: "Roe"}}},
+		{ID: 2, Title: "C, again"},
+	}
+
+	t.Run("JSON by default", func(t *testing.T) {
+		rec := respond("", http.StatusOK, gin.H{"payload": books[0]})
+		as.Equal(http.StatusOK, rec.Code)
+		as.Contains(rec.Header().Get("Content-Type"), MIMEJSON)
+		as.Equal("Accept", rec.Header().Get("Vary"))
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		rec := respond("application/xml", http.StatusOK, gin.H{"payload": books[0]})
+		as.Contains(rec.Header().Get("Content-Type"), MIMEXML)
+		as.Contains(rec.Body.String(), "<response><payload><authors><item><id>7</id><name>John</name><surname>Doe</surname></item>")
+		as.Contains(rec.Body.String(), "<title>Go</title>")
+	})
+
+	t.Run("CSV flattens nested lists", func(t *testing.T) {
+		rec := respond("text/csv", http.StatusOK, gin.H{"payload": books})
+		as.Equal(http.StatusOK, rec.Code)
+		as.Equal("id,authors,title\n1,John Doe; Jane Roe,Go\n2,,\"C, again\"\n", rec.Body.String())
+	})
+
+	t.Run("CSV is not offered for a single resource", func(t *testing.T) {
+		rec := respond("text/csv", http.StatusOK, gin.H{"payload": books[0]})
+		as.Equal(http.StatusNotAcceptable, rec.Code)
+	})
+
+	t.Run("errors fall back to JSON", func(t *testing.T) {
+		rec := respond("text/csv", http.StatusNotFound, gin.H{"error": "book not found"})
+		as.Equal(http.StatusNotFound, rec.Code)
+		as.Contains(rec.Header().Get("Content-Type"), MIMEJSON)
+	})
+
+	t.Run("MessagePack", func(t *testing.T) {
+		rec := respond("application/msgpack", http.StatusOK, gin.H{"payload": books[0]})
+		var out map[string]interface{}
+		as.NoError(codec.NewDecoderBytes(rec.Body.Bytes(), msgpackHandle).Decode(&out))
+		payload := out["payload"].(map[interface{}]interface{})
+		as.Equal("Go", payload["title"])
+		as.EqualValues(1, payload["id"])
+	})
+}
+
+func TestBind(t *testing.T) {
+	as := assert.New(t)
+	bind := func(contentType string, body []byte) (request, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", contentType)
+		var r request
+		err := Bind(c, &r)
+		return r, err
+	}
+
+	r, err := bind("application/json", []byte(`{"title":"Go","books":["1"]}`))
+	as.NoError(err)
+	as.Equal(request{Title: "Go", Books: []string{"1"}}, r)
+
+	r, err = bind("text/xml; charset=utf-8", []byte(`<request><title>Go</title><books><item>1</item><item>2</item></books></request>`))
+	as.NoError(err)
+	as.Equal(request{Title: "Go", Books: []string{"1", "2"}}, r)
+
+	var buf bytes.Buffer
+	as.NoError(codec.NewEncoder(&buf, msgpackHandle).Encode(map[string]interface{}{"title": "Go", "books": []string{"1"}}))
+	r, err = bind("application/msgpack", buf.Bytes())
+	as.NoError(err)
+	as.Equal(request{Title: "Go", Books: []string{"1"}}, r)
+
+	_, err = bind("text/csv", []byte("title\nGo\n"))
+	as.ErrorIs(err, ErrUnsupportedMediaType)
+	as.Equal(http.StatusUnsupportedMediaType, BindStatus(err))
+	_, err = bind("application/json", []byte(strings.Repeat("{", 2)))
+	as.Equal(http.StatusBadRequest, BindStatus(err))
+}
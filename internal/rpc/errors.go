@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"geniuscrew/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error returns the status for a service error: missing records are
+// NotFound, duplicates AlreadyExists, invalid credentials Unauthenticated
+// and authorization failures PermissionDenied; an unreachable dependency is
+// Unavailable. Errors caused by the call context ending keep its code;
+// anything else is Internal.
+func Error(ctx context.Context, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound), errors.Is(err, domain.ErrBookNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrDuplicateRecord):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrUnauthorized):
+		code = codes.Unauthenticated
+	case errors.Is(err, domain.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrUnknownField):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(ctx.Err(), context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+// InvalidArgument returns an InvalidArgument status carrying the failed
+// fields, as returned by appvalidator.InputValidator, as BadRequest details.
+func InvalidArgument(fields map[string]string) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	details := &errdetails.BadRequest{}
+	for _, name := range names {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       name,
+			Description: fields[name],
+		})
+	}
+	st, err := status.New(codes.InvalidArgument, "invalid input").WithDetails(details)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid input")
+	}
+	return st.Err()
+}
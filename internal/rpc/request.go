@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"geniuscrew/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IDs returns the ids of a batch read, which must be positive and at most
+// domain.MaxLimit of them, as the services take them.
+func IDs(ids []int64) ([]int, error) {
+	if len(ids) > domain.MaxLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids may be read at once", domain.MaxLimit)
+	}
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id < 1 {
+			return nil, status.Error(codes.InvalidArgument, "invalid id parameter")
+		}
+		out = append(out, int(id))
+	}
+	return out, nil
+}
+
+// Page returns the limit and offset of a search. An unset limit is
+// domain.MaxLimit, so that searches are always paged; a larger limit or a
+// negative offset is an InvalidArgument.
+func Page(limit, offset int32) (int, int, error) {
+	if limit < 0 || limit > domain.MaxLimit {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", domain.MaxLimit)
+	}
+	if offset < 0 {
+		return 0, 0, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+	if limit == 0 {
+		limit = domain.MaxLimit
+	}
+	return int(limit), int(offset), nil
+}
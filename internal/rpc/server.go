@@ -0,0 +1,307 @@
+// Package rpc holds what the gRPC handlers share: the server with its
+// interceptors and the mapping of service errors to status codes.
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/metrics"
+	"geniuscrew/internal/middleware"
+	"geniuscrew/internal/ratelimit"
+	"geniuscrew/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// readPrefixes name the methods anonymous callers may invoke, like the GET
+// routes of the REST API.
+var readPrefixes = []string{"Get", "BatchGet", "Search"}
+
+// NewServer returns a gRPC server that traces every call, recovers from
+// panics, logs and counts every call, bounds calls without an earlier
+// deadline by timeout, authenticates callers with the credentials in the
+// authorization or x-api-key metadata, and rate limits them like the REST
+// API, with the same limits and, given the same store, the same buckets.
+// Server reflection and the health service are registered on it; the
+// returned health server reports SERVING until it is shut down.
+func NewServer(a *auth.Authenticator, store ratelimit.Store, limits map[string]ratelimit.Limit, timeout time.Duration) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		tracer,
+		recoverer,
+		accessLog(slog.Default().With("layer", "handler")),
+		deadline(timeout),
+		rateLimitCredentials(store, limits[middleware.RateLimitAuth]),
+		authenticate(a),
+		rateLimit(store, limits),
+	))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	return server, healthServer
+}
+
+// tracer starts a server span per call, continuing the trace named in the
+// incoming traceparent metadata.
+func tracer(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	service, method := splitMethod(info.FullMethod)
+	ctx, span := tracing.Tracer().Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", method),
+		),
+	)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded, codes.Unimplemented:
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+	return resp, err
+}
+
+// metadataCarrier lets the propagator read incoming gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	if values := metadata.MD(m).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func recoverer(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "panic serving gRPC call", "method", info.FullMethod, "panic", r, "stack", string(debug.Stack()))
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func accessLog(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(start)
+
+		code := status.Code(err)
+		metrics.GRPCRequests.WithLabelValues(info.FullMethod, code.String()).Inc()
+		metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code.String()).Observe(elapsed.Seconds())
+
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+		}
+		if p, ok := auth.FromContext(ctx); ok {
+			attrs = append(attrs, slog.String("subject", p.Subject))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		log.LogAttrs(ctx, level, "grpc call", attrs...)
+		return resp, err
+	}
+}
+
+// deadline applies timeout to calls whose client set no deadline, or a
+// later one. A zero or negative timeout leaves calls unbounded.
+func deadline(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// authenticate stores the caller's identity in the context. Calls with
+// invalid credentials are rejected, as are unauthenticated calls to methods
+// that change the catalog.
+func authenticate(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/grpc.") {
+			// health and reflection
+			return handler(ctx, req)
+		}
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, info.FullMethod, nil)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, key := range []string{"Authorization", auth.APIKeyHeader} {
+			if values := md.Get(key); len(values) > 0 {
+				r.Header.Set(key, values[0])
+			}
+		}
+		principal, ok, err := a.Authenticate(r)
+		if err != nil {
+			return nil, Error(ctx, err)
+		}
+		if !ok {
+			if !isRead(info.FullMethod) {
+				return nil, status.Error(codes.Unauthenticated, "authentication required")
+			}
+			return handler(ctx, req)
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// rateLimitCredentials limits the calls carrying credentials per peer IP
+// before authenticate checks them, as middleware.RateLimitCredentials does.
+func rateLimitCredentials(store ratelimit.Store, limit ratelimit.Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if limit.Burst <= 0 || (len(md.Get("Authorization")) == 0 && len(md.Get(auth.APIKeyHeader)) == 0) {
+			return handler(ctx, req)
+		}
+		if err := take(ctx, store, middleware.RateLimitAuth+":ip:"+peerIP(ctx), limit); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimit applies the limit of the method's class to a bucket per caller,
+// keyed as the REST API keys them: the API key, the JWT subject or else the
+// peer IP. Calls over the limit get ResourceExhausted with RetryInfo.
+func rateLimit(store ratelimit.Store, limits map[string]ratelimit.Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/grpc.") {
+			// health and reflection
+			return handler(ctx, req)
+		}
+		class := rateLimitClass(info.FullMethod)
+		limit, ok := limits[class]
+		if !ok || limit.Burst <= 0 {
+			return handler(ctx, req)
+		}
+		if err := take(ctx, store, class+":"+clientKey(ctx), limit); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// take takes a token from the bucket at key and returns ResourceExhausted
+// when there is none. Store failures let calls through.
+func take(ctx context.Context, store ratelimit.Store, key string, limit ratelimit.Limit) error {
+	result, err := store.Take(ctx, key, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+	st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(result.RetryAfter),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return st.Err()
+}
+
+func rateLimitClass(fullMethod string) string {
+	_, method := splitMethod(fullMethod)
+	switch {
+	case strings.HasPrefix(method, "Search"):
+		return middleware.RateLimitSearch
+	case isRead(fullMethod):
+		return middleware.RateLimitRead
+	default:
+		return middleware.RateLimitWrite
+	}
+}
+
+func clientKey(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		if p.KeyID != "" {
+			return "key:" + p.KeyID
+		}
+		return "sub:" + p.Subject
+	}
+	return "ip:" + peerIP(ctx)
+}
+
+// peerIP returns the IP the call came from, without the port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// splitMethod returns the service and the method of /service/method.
+func splitMethod(fullMethod string) (string, string) {
+	i := strings.LastIndex(fullMethod, "/")
+	return strings.TrimPrefix(fullMethod[:i+1], "/"), fullMethod[i+1:]
+}
+
+func isRead(fullMethod string) bool {
+	_, method := splitMethod(fullMethod)
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"geniuscrew/internal/middleware"
+	"geniuscrew/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var okHandler grpc.UnaryHandler = func(ctx context.Context, req interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+// fromPeer returns a context for an incoming call from ip with md.
+func fromPeer(ip string, md metadata.MD) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}})
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestRateLimit(t *testing.T) {
+	as := assert.New(t)
+	search := &grpc.UnaryServerInfo{FullMethod: "/geniuscrew.catalog.v1.BookService/SearchBooks"}
+
+	t.Run("input error: callers over the limit are exhausted", func(t *testing.T) {
+		limit := rateLimit(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{middleware.RateLimitSearch: {Rate: 0.001, Burst: 1}})
+		_, err := limit(fromPeer("203.0.113.7", nil), nil, search, okHandler)
+		as.NoError(err)
+
+		_, err = limit(fromPeer("203.0.113.7", nil), nil, search, okHandler)
+		st := status.Convert(err)
+		as.Equal(codes.ResourceExhausted, st.Code())
+		as.Len(st.Details(), 1)
+		as.Positive(st.Details()[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+
+		// another caller has their own bucket
+		_, err = limit(fromPeer("203.0.113.8", nil), nil, search, okHandler)
+		as.NoError(err)
+	})
+
+	t.Run("happy path: authenticated callers are limited by key", func(t *testing.T) {
+		limit := rateLimit(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{middleware.RateLimitSearch: {Rate: 0.001, Burst: 1}})
+		ctx := auth.WithPrincipal(fromPeer("203.0.113.7", nil), auth.Principal{Subject: "svc", KeyID: "k1"})
+		_, err := limit(ctx, nil, search, okHandler)
+		as.NoError(err)
+		// anonymous calls from the same IP do not share the key's bucket
+		_, err = limit(fromPeer("203.0.113.7", nil), nil, search, okHandler)
+		as.NoError(err)
+	})
+
+	t.Run("happy path: health checks are not limited", func(t *testing.T) {
+		limit := rateLimit(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{middleware.RateLimitWrite: {Rate: 0.001, Burst: 1}})
+		health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+		for i := 0; i < 3; i++ {
+			_, err := limit(fromPeer("203.0.113.7", nil), nil, health, okHandler)
+			as.NoError(err)
+		}
+	})
+}
+
+func TestRateLimitCredentials(t *testing.T) {
+	as := assert.New(t)
+	limit := rateLimitCredentials(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.001, Burst: 1})
+	get := &grpc.UnaryServerInfo{FullMethod: "/geniuscrew.catalog.v1.BookService/GetBook"}
+	withKey := metadata.Pairs(auth.APIKeyHeader, "gc_guess")
+
+	_, err := limit(fromPeer("203.0.113.7", withKey), nil, get, okHandler)
+	as.NoError(err)
+	_, err = limit(fromPeer("203.0.113.7", withKey), nil, get, okHandler)
+	as.Equal(codes.ResourceExhausted, status.Code(err))
+
+	// calls without credentials are not counted
+	_, err = limit(fromPeer("203.0.113.7", nil), nil, get, okHandler)
+	as.NoError(err)
+}
+
+func TestTracer(t *testing.T) {
+	as := assert.New(t)
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/geniuscrew.catalog.v1.BookService/GetBook"}
+
+	t.Run("happy path: continues the caller's trace", func(t *testing.T) {
+		md := metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		_, err := tracer(fromPeer("203.0.113.7", md), nil, info, okHandler)
+		as.NoError(err)
+
+		spans := recorder.Ended()
+		span := spans[len(spans)-1]
+		as.Equal("geniuscrew.catalog.v1.BookService/GetBook", span.Name())
+		as.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		as.Equal("00f067aa0ba902b7", span.Parent().SpanID().String())
+		as.Equal(otelcodes.Unset, span.Status().Code)
+	})
+
+	t.Run("service error: the span is failed", func(t *testing.T) {
+		failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, Error(ctx, domain.ErrUnavailable)
+		}
+		_, err := tracer(fromPeer("203.0.113.7", nil), nil, info, failing)
+		as.Equal(codes.Unavailable, status.Code(err))
+
+		spans := recorder.Ended()
+		as.Equal(otelcodes.Error, spans[len(spans)-1].Status().Code)
+	})
+}
+
+func TestPage(t *testing.T) {
+	as := assert.New(t)
+
+	limit, offset, err := Page(0, 0)
+	as.NoError(err)
+	as.Equal(domain.MaxLimit, limit)
+	as.Equal(0, offset)
+
+	limit, offset, err = Page(10, 20)
+	as.NoError(err)
+	as.Equal(10, limit)
+	as.Equal(20, offset)
+
+	_, _, err = Page(domain.MaxLimit+1, 0)
+	as.Equal(codes.InvalidArgument, status.Code(err))
+	_, _, err = Page(10, -1)
+	as.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestIDs(t *testing.T) {
+	as := assert.New(t)
+
+	ids, err := IDs([]int64{1, 2})
+	as.NoError(err)
+	as.Equal([]int{1, 2}, ids)
+
+	_, err = IDs([]int64{1, 0})
+	as.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = IDs(make([]int64, domain.MaxLimit+1))
+	as.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeadline(t *testing.T) {
+	as := assert.New(t)
+	var got time.Time
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = ctx.Deadline()
+		return nil, nil
+	}
+	_, err := deadline(time.Second)(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	as.NoError(err)
+	as.WithinDuration(time.Now().Add(time.Second), got, 100*time.Millisecond)
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"geniuscrew/internal/dbtest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a tracer provider exporting every span, once ended, to the
+// returned in-memory exporter for the rest of the test.
+func record(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+	return exporter
+}
+
+func attr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestEnd(t *testing.T) {
+	as := assert.New(t)
+	exporter := record(t)
+
+	_, span := Tracer().Start(context.Background(), "ok")
+	as.NoError(End(span, nil))
+	span.End()
+	_, span = Tracer().Start(context.Background(), "failed")
+	as.EqualError(End(span, errors.New("boom")), "boom")
+	span.End()
+
+	spans := exporter.GetSpans()
+	as.Len(spans, 2)
+	as.Equal(codes.Unset, spans[0].Status.Code)
+	as.Equal(codes.Error, spans[1].Status.Code)
+	as.Equal("boom", spans[1].Status.Description)
+	as.Len(spans[1].Events, 1)
+}
+
+func TestInit(t *testing.T) {
+	as := assert.New(t)
+
+	flush, err := Init(context.Background(), "none", "geniuscrew")
+	as.NoError(err)
+	as.NoError(flush(context.Background()))
+
+	_, err = Init(context.Background(), "jaeger", "geniuscrew")
+	as.ErrorContains(err, "jaeger")
+}
+
+func TestGormPlugin(t *testing.T) {
+	as := assert.New(t)
+	exporter := record(t)
+	db, sql := dbtest.New(t)
+	as.NoError(db.Use(GormPlugin{}))
+
+	t.Run("happy path: statements are child spans of the request", func(t *testing.T) {
+		exporter.Reset()
+		sql.ExpectQuery("SELECT \\* FROM `books` WHERE isbn = ").WithArgs("978160309028").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		ctx, parent := Tracer().Start(context.Background(), "GET /api/v1/books")
+
+		var rows []struct{ ID int }
+		as.NoError(db.WithContext(ctx).Table("books").Where("isbn = ?", "978160309028").Find(&rows).Error)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		as.Len(spans, 2)
+		statement := spans[0]
+		as.Equal("sql.query", statement.Name)
+		as.Equal(trace.SpanKindClient, statement.SpanKind)
+		as.Equal(parent.SpanContext().SpanID(), statement.Parent.SpanID())
+		as.Equal("books", attr(statement, "db.sql.table").AsString())
+		// the statement carries placeholders, not the values
+		as.NotContains(attr(statement, "db.statement").AsString(), "978160309028")
+		as.Equal(codes.Unset, statement.Status.Code)
+	})
+
+	t.Run("database error: the statement span is failed", func(t *testing.T) {
+		exporter.Reset()
+		sql.ExpectQuery("SELECT \\* FROM `books`").WillReturnError(errors.New("connection refused"))
+
+		var rows []struct{ ID int }
+		as.Error(db.WithContext(context.Background()).Table("books").Find(&rows).Error)
+
+		spans := exporter.GetSpans()
+		as.Len(spans, 1)
+		as.Equal(codes.Error, spans[0].Status.Code)
+	})
+}
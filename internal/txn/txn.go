@@ -0,0 +1,72 @@
+// Package txn runs repository calls in one database transaction, carried by
+// the context they are given.
+package txn
+
+import (
+	"context"
+	"geniuscrew/domain"
+
+	"gorm.io/gorm"
+)
+
+type ctxKey struct{}
+
+// state is the transaction a context carries.
+type state struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor returns a Transactor running transactions on db.
+func NewTransactor(db *gorm.DB) domain.Transactor {
+	return &transactor{db}
+}
+
+// WithinTx runs fn in a transaction, committed if fn returns nil and rolled
+// back otherwise. Calls nested in fn join the outer transaction.
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(ctxKey{}).(*state); ok {
+		return fn(ctx)
+	}
+	s := &state{}
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		s.tx = tx
+		return fn(context.WithValue(ctx, ctxKey{}, s))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range s.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// AfterCommit calls fn once the transaction ctx carries is committed, and
+// not at all if it is rolled back. Without a transaction fn is called now.
+func (t *transactor) AfterCommit(ctx context.Context, fn func()) {
+	if s, ok := ctx.Value(ctxKey{}).(*state); ok {
+		s.afterCommit = append(s.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// InTx reports whether ctx carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKey{}).(*state)
+	return ok
+}
+
+// DB returns the transaction ctx carries, or db outside of one, bound to
+// ctx. Repositories taking part in transactions run their statements on it.
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if s, ok := ctx.Value(ctxKey{}).(*state); ok {
+		return s.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
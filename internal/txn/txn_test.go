@@ -0,0 +1,71 @@
+package txn
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/dbtest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithinTx(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	t.Run("happy path: commits and then runs the after-commit hooks", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM `books`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		transactor := NewTransactor(db)
+		var calls []string
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			as.True(InTx(ctx))
+			transactor.AfterCommit(ctx, func() { calls = append(calls, "after commit") })
+			calls = append(calls, "in tx")
+			return DB(ctx, db).Where("id = ?", 1).Delete(&domain.Book{}).Error
+		})
+		as.NoError(err)
+		as.Equal([]string{"in tx", "after commit"}, calls)
+	})
+
+	t.Run("nested calls join the outer transaction", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		transactor := NewTransactor(db)
+		err := transactor.WithinTx(ctx, func(outer context.Context) error {
+			return transactor.WithinTx(outer, func(inner context.Context) error {
+				as.Same(DB(outer, db).Statement.ConnPool, DB(inner, db).Statement.ConnPool)
+				return nil
+			})
+		})
+		as.NoError(err)
+	})
+
+	t.Run("error: rolls back and skips the after-commit hooks", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		transactor := NewTransactor(db)
+		failed := errors.New("failed")
+		called := false
+		err := transactor.WithinTx(ctx, func(ctx context.Context) error {
+			transactor.AfterCommit(ctx, func() { called = true })
+			return failed
+		})
+		as.True(errors.Is(err, failed))
+		as.False(called)
+	})
+
+	t.Run("outside a transaction hooks run at once", func(t *testing.T) {
+		db, _ := dbtest.New(t)
+		called := false
+		NewTransactor(db).AfterCommit(ctx, func() { called = true })
+		as.True(called)
+		as.False(InTx(ctx))
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/txn"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mysqlOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewMySqlOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &mysqlOutboxRepository{db}
+}
+
+// Create takes the Seq of event from the counter, which stays locked until
+// the transaction ctx carries ends. Without a transaction, it runs its own.
+func (m *mysqlOutboxRepository) Create(ctx context.Context, event *domain.OutboxEvent) error {
+	create := func(tx *gorm.DB) error {
+		seq, err := nextSeq(tx)
+		if err != nil {
+			return err
+		}
+		event.Seq = seq
+		return tx.Create(event).Error
+	}
+	if txn.InTx(ctx) {
+		return create(txn.DB(ctx, m.db))
+	}
+	return m.db.WithContext(ctx).Transaction(create)
+}
+
+// ClaimDue skips events with an earlier unsent event of the same aggregate,
+// then moves the next attempt of each due event only if no other instance
+// moved it since it was read, and returns those it moved.
+func (m *mysqlOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	var due []domain.OutboxEvent
+	err := m.db.WithContext(ctx).Where("sent_at IS NULL AND next_attempt_at <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM outbox_events earlier WHERE earlier.aggregate = outbox_events.aggregate AND earlier.sent_at IS NULL AND earlier.id < outbox_events.id)").
+		Order("id").Limit(limit).Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+	claimed := due[:0]
+	for _, event := range due {
+		result := m.db.WithContext(ctx).Model(&domain.OutboxEvent{}).
+			Where("id = ? AND sent_at IS NULL AND next_attempt_at = ?", event.ID, event.NextAttemptAt).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 1 {
+			event.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mysqlOutboxRepository) Update(ctx context.Context, event *domain.OutboxEvent) error {
+	err := m.db.WithContext(ctx).Model(event).
+		Select("Attempts", "NextAttemptAt", "LastError", "SentAt").
+		Updates(event).Error
+	return err
+}
+
+func (m *mysqlOutboxRepository) DeleteSent(ctx context.Context, t time.Time) (int64, error) {
+	result := m.db.WithContext(ctx).Where("sent_at < ?", t).Delete(&domain.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func (m *mysqlOutboxRepository) After(ctx context.Context, seq int, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := m.db.WithContext(ctx).Where("seq > ?", seq).Order("seq").Limit(limit).Find(&events).Error
+	if err != nil {
+		return []domain.OutboxEvent{}, err
+	}
+	return events, nil
+}
+
+func (m *mysqlOutboxRepository) Latest(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := m.db.WithContext(ctx).Where("seq > 0").Order("seq DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return []domain.OutboxEvent{}, err
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
+
+func (m *mysqlOutboxRepository) FirstSeq(ctx context.Context) (int, error) {
+	var first sql.NullInt64
+	err := m.db.WithContext(ctx).Model(&domain.OutboxEvent{}).Where("seq > 0").Select("MIN(seq)").Scan(&first).Error
+	if err != nil || first.Valid {
+		return int(first.Int64), err
+	}
+	last, err := m.LastSeq(ctx)
+	return last + 1, err
+}
+
+func (m *mysqlOutboxRepository) LastSeq(ctx context.Context) (int, error) {
+	var counter domain.EventSequence
+	err := m.db.WithContext(ctx).Where("id = ?", 1).Limit(1).Find(&counter).Error
+	return counter.Value, err
+}
+
+// nextSeq takes the next sequence number in tx. The first event creates the
+// counter; a concurrent first event waits for it and then takes the number
+// after.
+func nextSeq(tx *gorm.DB) (int, error) {
+	for attempt := 1; ; attempt++ {
+		result := tx.Model(&domain.EventSequence{}).Where("id = ?", 1).UpdateColumn("value", gorm.Expr("value + 1"))
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		if result.RowsAffected == 1 {
+			break
+		}
+		if attempt == 2 {
+			return 0, errors.New("event sequence counter is missing")
+		}
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.EventSequence{ID: 1}).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+	var counter domain.EventSequence
+	err := tx.Where("id = ?", 1).Take(&counter).Error
+	return counter.Value, err
+}
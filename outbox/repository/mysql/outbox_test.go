@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/dbtest"
+	"geniuscrew/internal/txn"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	as := assert.New(t)
+	ctx := context.Background()
+
+	t.Run("happy path: takes the next sequence number in its own transaction", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `event_sequences` SET `value`=value \\+ 1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT \\* FROM `event_sequences`").WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, 42))
+		mock.ExpectExec("INSERT INTO `outbox_events`").WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+		event := domain.OutboxEvent{EventID: "evt_1", Type: domain.EventBookCreated, Aggregate: "book/1"}
+		as.NoError(NewMySqlOutboxRepository(db).Create(ctx, &event))
+		as.Equal(42, event.Seq)
+		as.Equal(7, event.ID)
+	})
+
+	t.Run("first event: creates the counter", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `event_sequences`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO `event_sequences` .* ON DUPLICATE KEY UPDATE").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE `event_sequences`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT \\* FROM `event_sequences`").WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, 1))
+		mock.ExpectExec("INSERT INTO `outbox_events`").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		event := domain.OutboxEvent{EventID: "evt_1"}
+		as.NoError(NewMySqlOutboxRepository(db).Create(ctx, &event))
+		as.Equal(1, event.Seq)
+	})
+
+	t.Run("in a transaction: joins it", func(t *testing.T) {
+		db, mock := dbtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `event_sequences`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT \\* FROM `event_sequences`").WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, 5))
+		mock.ExpectExec("INSERT INTO `outbox_events`").WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+		repo := NewMySqlOutboxRepository(db)
+		event := domain.OutboxEvent{EventID: "evt_1"}
+		err := txn.NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+			return repo.Create(ctx, &event)
+		})
+		as.NoError(err)
+		as.Equal(5, event.Seq)
+	})
+}
+
+func TestClaimDue(t *testing.T) {
+	as := assert.New(t)
+	db, mock := dbtest.New(t)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	mock.ExpectQuery("SELECT \\* FROM `outbox_events` WHERE \\(sent_at IS NULL AND next_attempt_at <= \\?\\) AND \\(NOT EXISTS").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "next_attempt_at"}).AddRow(1, "evt_1", due).AddRow(2, "evt_2", due))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `outbox_events` SET `next_attempt_at`=\\?").WithArgs(now.Add(time.Minute), 1, due).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// another instance claimed the second event first
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `outbox_events` SET `next_attempt_at`=\\?").WithArgs(now.Add(time.Minute), 2, due).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	claimed, err := NewMySqlOutboxRepository(db).ClaimDue(context.Background(), now, time.Minute, 10)
+	as.NoError(err)
+	as.Len(claimed, 1)
+	as.Equal("evt_1", claimed[0].EventID)
+	as.Equal(now.Add(time.Minute), claimed[0].NextAttemptAt)
+}
+
+func TestLatest(t *testing.T) {
+	as := assert.New(t)
+	db, mock := dbtest.New(t)
+	mock.ExpectQuery("SELECT \\* FROM `outbox_events` WHERE seq > 0 ORDER BY seq DESC LIMIT 3").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}).AddRow(9, 9).AddRow(8, 8).AddRow(7, 7))
+
+	events, err := NewMySqlOutboxRepository(db).Latest(context.Background(), 3)
+	as.NoError(err)
+	as.Equal([]int{7, 8, 9}, []int{events[0].Seq, events[1].Seq, events[2].Seq})
+}
+
+func TestFirstSeq(t *testing.T) {
+	as := assert.New(t)
+	db, mock := dbtest.New(t)
+	// every event was deleted: the next one takes the number after the counter
+	mock.ExpectQuery("SELECT MIN\\(seq\\) FROM `outbox_events`").WillReturnRows(sqlmock.NewRows([]string{"MIN(seq)"}).AddRow(nil))
+	mock.ExpectQuery("SELECT \\* FROM `event_sequences`").WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, 12))
+
+	first, err := NewMySqlOutboxRepository(db).FirstSeq(context.Background())
+	as.NoError(err)
+	as.Equal(13, first)
+}
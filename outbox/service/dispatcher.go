@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/metrics"
+	"log/slog"
+	"time"
+)
+
+// batchSize is the number of events claimed at once.
+const batchSize = 50
+
+// DispatcherConfig tunes how the outbox is emptied.
+type DispatcherConfig struct {
+	// Sinks receive every event, in this order.
+	Sinks []Sink
+	// Timeout bounds handing an event to each sink.
+	Timeout time.Duration
+	// Backoff is the wait after the first failed attempt at an event,
+	// doubled after each further one up to MaxBackoff. Events are retried
+	// until every sink has taken them.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// PollInterval is how often the dispatcher looks for events recorded
+	// by other instances or due for a retry.
+	PollInterval time.Duration
+	// Retention is how long sent events are kept.
+	Retention time.Duration
+}
+
+// Dispatcher hands the events recorded in the outbox to the sinks and marks
+// them sent. Delivery is at least once: an event may reach a sink again
+// when another sink failed it or the process stopped before marking it sent,
+// and sinks recognize it by its ID. Events of one aggregate reach the sinks
+// in the order they were recorded; a failing event holds back the later
+// events of its aggregate until it is sent.
+type Dispatcher struct {
+	outboxRepository domain.OutboxRepository
+	cfg              DispatcherConfig
+	wake             chan struct{}
+	now              func() time.Time
+}
+
+func NewDispatcher(o domain.OutboxRepository, cfg DispatcherConfig) *Dispatcher {
+	return &Dispatcher{outboxRepository: o, cfg: cfg, wake: make(chan struct{}, 1), now: time.Now}
+}
+
+// Notify makes Run look for events now rather than at the next poll.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches events until ctx is done, and deletes sent events past
+// their retention at every poll.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		d.DispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.purge(ctx)
+		case <-d.wake:
+		}
+	}
+}
+
+// DispatchDue makes one attempt at every event due now. Only the oldest
+// unsent event of each aggregate is claimed at a time, so claiming goes on
+// while events are sent. An event is claimed for twice the time its sinks
+// may take, after which another instance may dispatch it if this one did not
+// record an outcome.
+func (d *Dispatcher) DispatchDue(ctx context.Context) {
+	lease := 2 * d.cfg.Timeout * time.Duration(max(len(d.cfg.Sinks), 1))
+	for ctx.Err() == nil {
+		batch, err := d.outboxRepository.ClaimDue(ctx, d.now(), lease, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "claiming outbox events failed", "layer", "service", "error", err)
+			}
+			return
+		}
+		sent := 0
+		for i := range batch {
+			if d.dispatch(ctx, &batch[i]) {
+				sent++
+			}
+		}
+		if sent == 0 {
+			return
+		}
+	}
+}
+
+// dispatch hands event to every sink, records the outcome and reports
+// whether the event was sent.
+func (d *Dispatcher) dispatch(ctx context.Context, event *domain.OutboxEvent) bool {
+	var errs []error
+	for _, sink := range d.cfg.Sinks {
+		sinkCtx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+		err := sink.Publisher.Publish(sinkCtx, event.Event())
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name, err))
+		}
+	}
+	now := d.now()
+	event.Attempts++
+	outcome := "sent"
+	if err := errors.Join(errs...); err != nil {
+		outcome = "retried"
+		event.LastError = truncate(err.Error())
+		event.NextAttemptAt = now.Add(d.backoff(event.Attempts))
+		slog.WarnContext(ctx, "dispatching outbox event failed", "layer", "service", "event_id", event.EventID, "aggregate", event.Aggregate, "attempts", event.Attempts, "error", err)
+	} else {
+		event.LastError = ""
+		event.SentAt = &now
+	}
+	metrics.OutboxEvents.WithLabelValues(event.Type, outcome).Inc()
+	if err := d.outboxRepository.Update(ctx, event); err != nil {
+		// the claim lapses and the event is dispatched again
+		slog.ErrorContext(ctx, "recording outbox event failed", "layer", "service", "event_id", event.EventID, "error", err)
+		return false
+	}
+	return outcome == "sent"
+}
+
+func (d *Dispatcher) purge(ctx context.Context) {
+	deleted, err := d.outboxRepository.DeleteSent(ctx, d.now().Add(-d.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "purging outbox failed", "layer", "service", "error", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		slog.DebugContext(ctx, "outbox purged", "layer", "service", "events", deleted)
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.Backoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
+
+func truncate(message string) string {
+	if len(message) > 1024 {
+		return message[:1024]
+	}
+	return message
+}
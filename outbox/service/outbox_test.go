@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recorder is a sink keeping the events it is given, failing while err is
+// set.
+type recorder struct {
+	events []domain.Event
+	err    error
+}
+
+func (r *recorder) Publish(ctx context.Context, event domain.Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+// transactor runs fn without a database and calls the AfterCommit functions
+// when fn succeeds.
+type transactor struct {
+	afterCommit []func()
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.afterCommit = nil
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	for _, f := range t.afterCommit {
+		f()
+	}
+	return nil
+}
+
+func (t *transactor) AfterCommit(ctx context.Context, fn func()) {
+	t.afterCommit = append(t.afterCommit, fn)
+}
+
+func TestPublish(t *testing.T) {
+	as := assert.New(t)
+	outboxRepo := &repository.OutboxRepositoryMock{}
+	tx := &transactor{}
+	notified := 0
+	publisher := NewOutboxPublisher(outboxRepo, tx, func() { notified++ })
+	event := domain.NewEvent(domain.EventBookCreated, "book/1", map[string]string{"title": "Dune"})
+
+	t.Run("happy path: records the event and notifies once committed", func(t *testing.T) {
+		outboxRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+			return e.EventID == event.ID && e.Aggregate == "book/1" && string(e.Data) == `{"title":"Dune"}`
+		})).Return(nil).Once()
+		err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			err := publisher.Publish(ctx, event)
+			as.Equal(0, notified)
+			return err
+		})
+		as.NoError(err)
+		as.Equal(1, notified)
+		outboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("rollback: a failed write does not notify", func(t *testing.T) {
+		outboxRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := publisher.Publish(ctx, event); err != nil {
+				return err
+			}
+			return domain.ErrDuplicateRecord
+		})
+		as.True(errors.Is(err, domain.ErrDuplicateRecord))
+		as.Equal(1, notified)
+		outboxRepo.AssertExpectations(t)
+	})
+}
+
+func newDispatcher(outboxRepo domain.OutboxRepository, now time.Time, sinks ...Sink) *Dispatcher {
+	d := NewDispatcher(outboxRepo, DispatcherConfig{
+		Sinks:        sinks,
+		Timeout:      time.Second,
+		Backoff:      time.Second,
+		MaxBackoff:   3 * time.Second,
+		PollInterval: time.Minute,
+		Retention:    time.Hour,
+	})
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func TestDispatchDue(t *testing.T) {
+	as := assert.New(t)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	first := domain.OutboxEvent{ID: 1, EventID: "evt_1", Type: domain.EventBookCreated, Aggregate: "book/1", Data: []byte(`{"id":1}`)}
+	second := domain.OutboxEvent{ID: 2, EventID: "evt_2", Type: domain.EventBookUpdated, Aggregate: "book/1", Data: []byte(`{"id":1}`)}
+
+	t.Run("happy path: every sink gets the events in order and they are marked sent", func(t *testing.T) {
+		outboxRepo := &repository.OutboxRepositoryMock{}
+		// the second event of the aggregate is claimed once the first is sent
+		outboxRepo.On("ClaimDue", mock.Anything, now, 4*time.Second, batchSize).Return([]domain.OutboxEvent{first}, nil).Once()
+		outboxRepo.On("ClaimDue", mock.Anything, now, 4*time.Second, batchSize).Return([]domain.OutboxEvent{second}, nil).Once()
+		outboxRepo.On("ClaimDue", mock.Anything, now, 4*time.Second, batchSize).Return([]domain.OutboxEvent{}, nil).Once()
+		outboxRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+			return e.SentAt != nil && e.SentAt.Equal(now) && e.Attempts == 1
+		})).Return(nil).Twice()
+		a, b := &recorder{}, &recorder{}
+		newDispatcher(outboxRepo, now, Sink{Name: "a", Publisher: a}, Sink{Name: "b", Publisher: b}).DispatchDue(context.Background())
+		for _, sink := range []*recorder{a, b} {
+			as.Len(sink.events, 2)
+			as.Equal("evt_1", sink.events[0].ID)
+			as.Equal("evt_2", sink.events[1].ID)
+			as.Equal(json.RawMessage(`{"id":1}`), sink.events[0].Data)
+		}
+		outboxRepo.AssertExpectations(t)
+	})
+
+	t.Run("retry: a failing sink schedules the event again with backoff", func(t *testing.T) {
+		outboxRepo := &repository.OutboxRepositoryMock{}
+		event := first
+		event.Attempts = 2
+		outboxRepo.On("ClaimDue", mock.Anything, now, 4*time.Second, batchSize).Return([]domain.OutboxEvent{event}, nil).Once()
+		outboxRepo.On("Update", mock.Anything, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+			// the third failure waits four times the backoff, capped at the maximum
+			return e.SentAt == nil && e.Attempts == 3 && e.NextAttemptAt.Equal(now.Add(3*time.Second)) && e.LastError == "b: unavailable"
+		})).Return(nil).Once()
+		a, b := &recorder{}, &recorder{err: errors.New("unavailable")}
+		newDispatcher(outboxRepo, now, Sink{Name: "a", Publisher: a}, Sink{Name: "b", Publisher: b}).DispatchDue(context.Background())
+		as.Len(a.events, 1)
+		outboxRepo.AssertExpectations(t)
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	as := assert.New(t)
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+	for _, aggregate := range []string{"book/1", "author/2"} {
+		as.NoError(sink.Publish(context.Background(), domain.NewEvent(domain.EventBookDeleted, aggregate, json.RawMessage(`{"id":1}`))))
+	}
+	f, err := os.Open(path)
+	as.NoError(err)
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var event map[string]interface{}
+		as.NoError(json.Unmarshal(scanner.Bytes(), &event))
+		as.Equal(domain.EventBookDeleted, event["type"])
+		as.Equal(map[string]interface{}{"id": 1.0}, event["data"])
+	}
+	as.Equal(2, lines)
+}
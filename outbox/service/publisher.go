@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"geniuscrew/domain"
+	"time"
+)
+
+type outboxPublisher struct {
+	outboxRepository domain.OutboxRepository
+	transactor       domain.Transactor
+	notify           func()
+}
+
+// NewOutboxPublisher returns a publisher that records each event in the
+// outbox, in the transaction of the context it is given, and calls notify,
+// e.g. with Dispatcher.Notify, once that transaction is committed.
+func NewOutboxPublisher(o domain.OutboxRepository, t domain.Transactor, notify func()) domain.EventPublisher {
+	return &outboxPublisher{outboxRepository: o, transactor: t, notify: notify}
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	err = p.outboxRepository.Create(ctx, &domain.OutboxEvent{
+		EventID:       event.ID,
+		Type:          event.Type,
+		Aggregate:     event.Aggregate,
+		OccurredAt:    event.OccurredAt,
+		Data:          data,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	p.transactor.AfterCommit(ctx, p.notify)
+	return nil
+}
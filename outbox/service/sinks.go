@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"geniuscrew/domain"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Sink is a named destination of the events in the outbox.
+type Sink struct {
+	Name      string
+	Publisher domain.EventPublisher
+}
+
+type logSink struct{}
+
+// NewLogSink returns a sink writing each event to the service log.
+func NewLogSink() domain.EventPublisher {
+	return logSink{}
+}
+
+func (logSink) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "catalog event", "layer", "service", "event", event.Type, "event_id", event.ID, "aggregate", event.Aggregate, "data", string(data))
+	return nil
+}
+
+type fileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink returns a sink appending each event as a line of JSON to the
+// file at path, created if missing. Lines are synced to disk before the event
+// counts as sent.
+func NewFileSink(path string) domain.EventPublisher {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) Publish(ctx context.Context, event domain.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/internal/appvalidator"
+	"geniuscrew/internal/helpers"
+	"geniuscrew/internal/render"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultDeliveries is the number of deliveries listed without a limit.
+const defaultDeliveries = 50
+
+type WebhookHandler struct {
+	WebhookService domain.WebhookService
+}
+
+func NewWebhookHandler(router *gin.Engine, ws domain.WebhookService) {
+	handler := &WebhookHandler{
+		WebhookService: ws,
+	}
+	api := router.Group("/api/v1/admin")
+	api.POST("/webhooks", handler.CreateWebhook)
+	api.GET("/webhooks", handler.ListWebhooks)
+	api.GET("/webhooks/:id", handler.GetWebhook)
+	api.DELETE("/webhooks/:id", handler.DeleteWebhook)
+	api.GET("/webhooks/:id/deliveries", handler.ListDeliveries)
+	api.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+}
+
+func (p *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input struct {
+		URL         string   `json:"url" xml:"url" validate:"required,url,startswith=http,lte=2048"`
+		Description string   `json:"description" xml:"description" validate:"lte=255"`
+		Events      []string `json:"events" xml:"events>item" validate:"min=1,dive,required"`
+	}
+	if err := render.Bind(c, &input); err != nil {
+		render.Respond(c, render.BindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	inputErr := appvalidator.InputValidator(input)
+	if inputErr != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": inputErr})
+		return
+	}
+	var ctx = c.Request.Context()
+	hook := domain.Webhook{URL: input.URL, Description: input.Description, Events: input.Events}
+	secret, err := p.WebhookService.Create(ctx, &hook)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownEvent):
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "events_allowed": domain.EventTypes})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": hook, "secret": secret})
+}
+
+func (p *WebhookHandler) ListWebhooks(c *gin.Context) {
+	var ctx = c.Request.Context()
+	hooks, err := p.WebhookService.List(ctx)
+	if err != nil {
+		render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+		return
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": hooks})
+}
+
+func (p *WebhookHandler) GetWebhook(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	var ctx = c.Request.Context()
+	hook, err := p.WebhookService.Get(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": hook})
+}
+
+func (p *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	var ctx = c.Request.Context()
+	err = p.WebhookService.Delete(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (p *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id := c.Param("id")
+	err := appvalidator.IsIDValid(id)
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	limit, _, err := domain.ParsePage(c.Query("limit"), "")
+	if err != nil {
+		render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if limit == 0 {
+		limit = defaultDeliveries
+	}
+	var ctx = c.Request.Context()
+	deliveries, err := p.WebhookService.Deliveries(ctx, id, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusOK, gin.H{"payload": deliveries})
+}
+
+func (p *WebhookHandler) Redeliver(c *gin.Context) {
+	id := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+	for _, param := range []string{id, deliveryID} {
+		if err := appvalidator.IsIDValid(param); err != nil {
+			render.Respond(c, http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	var ctx = c.Request.Context()
+	delivery, err := p.WebhookService.Redeliver(ctx, id, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRecordNotFound):
+			render.Respond(c, http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		default:
+			render.Respond(c, helpers.ErrorStatus(ctx, err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	render.Respond(c, http.StatusAccepted, gin.H{"payload": delivery})
+}
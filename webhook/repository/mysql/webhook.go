@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mysqlWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewMySqlWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &mysqlWebhookRepository{db}
+}
+
+func (m *mysqlWebhookRepository) Create(ctx context.Context, hook *domain.Webhook) error {
+	err := m.db.WithContext(ctx).Create(hook).Error
+	return err
+}
+
+func (m *mysqlWebhookRepository) Get(ctx context.Context, id string) (domain.Webhook, error) {
+	var hook domain.Webhook
+	err := m.db.WithContext(ctx).Where("id = ?", id).First(&hook).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return domain.Webhook{}, domain.ErrRecordNotFound
+		default:
+			return domain.Webhook{}, err
+		}
+	}
+	return hook, nil
+}
+
+func (m *mysqlWebhookRepository) List(ctx context.Context) ([]domain.Webhook, error) {
+	var hooks []domain.Webhook
+	err := m.db.WithContext(ctx).Order("id").Find(&hooks).Error
+	if err != nil {
+		return []domain.Webhook{}, err
+	}
+	return hooks, nil
+}
+
+// Delete removes the webhook and its delivery log, including deliveries
+// still pending.
+func (m *mysqlWebhookRepository) Delete(ctx context.Context, id string) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&domain.Webhook{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrRecordNotFound
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&domain.WebhookDelivery{}).Error
+	})
+}
+
+func (m *mysqlWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := m.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+	return err
+}
+
+func (m *mysqlWebhookRepository) GetDelivery(ctx context.Context, webhookID, id string) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := m.db.WithContext(ctx).Where("id = ? AND webhook_id = ?", id, webhookID).First(&delivery).Error
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return domain.WebhookDelivery{}, domain.ErrRecordNotFound
+		default:
+			return domain.WebhookDelivery{}, err
+		}
+	}
+	return delivery, nil
+}
+
+func (m *mysqlWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := m.db.WithContext(ctx).Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDue moves the next attempt of each due delivery only if no other
+// instance moved it since it was read, and returns those it moved.
+func (m *mysqlWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var due []domain.WebhookDelivery
+	err := m.db.WithContext(ctx).Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+	claimed := due[:0]
+	for _, delivery := range due {
+		result := m.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, domain.DeliveryPending, delivery.NextAttemptAt).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 1 {
+			delivery.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, delivery)
+		}
+	}
+	return claimed, nil
+}
+
+// UpdateDelivery writes the outcome of an attempt.
+func (m *mysqlWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	err := m.db.WithContext(ctx).Model(delivery).
+		Select("Status", "Attempts", "NextAttemptAt", "ResponseStatus", "LastError", "DeliveredAt").
+		Updates(delivery).Error
+	return err
+}
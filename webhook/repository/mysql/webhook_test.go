@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/dbtest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDeliveries(t *testing.T) {
+	as := assert.New(t)
+	db, mock := dbtest.New(t)
+	// an event published again leaves the deliveries already queued as they are
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `webhook_deliveries` .* ON DUPLICATE KEY UPDATE `id`=`id`").WillReturnResult(sqlmock.NewResult(1, 0))
+	mock.ExpectCommit()
+
+	original := true
+	deliveries := []domain.WebhookDelivery{{WebhookID: 1, EventID: "evt_1", Status: domain.DeliveryPending, Original: &original}}
+	as.NoError(NewMySqlWebhookRepository(db).CreateDeliveries(context.Background(), deliveries))
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/internal/authz"
+)
+
+type authorizedWebhookService struct {
+	next   domain.WebhookService
+	policy *authz.Policy
+}
+
+// NewAuthorizedWebhookService wraps w so that every method requires the
+// webhooks:manage permission.
+func NewAuthorizedWebhookService(w domain.WebhookService, policy *authz.Policy) domain.WebhookService {
+	return &authorizedWebhookService{next: w, policy: policy}
+}
+
+func (a *authorizedWebhookService) Create(ctx context.Context, hook *domain.Webhook) (string, error) {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return "", err
+	}
+	return a.next.Create(ctx, hook)
+}
+
+func (a *authorizedWebhookService) List(ctx context.Context) ([]domain.Webhook, error) {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return nil, err
+	}
+	return a.next.List(ctx)
+}
+
+func (a *authorizedWebhookService) Get(ctx context.Context, id string) (domain.Webhook, error) {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return domain.Webhook{}, err
+	}
+	return a.next.Get(ctx, id)
+}
+
+func (a *authorizedWebhookService) Delete(ctx context.Context, id string) error {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id)
+}
+
+func (a *authorizedWebhookService) Deliveries(ctx context.Context, id string, limit int) ([]domain.WebhookDelivery, error) {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return nil, err
+	}
+	return a.next.Deliveries(ctx, id, limit)
+}
+
+func (a *authorizedWebhookService) Redeliver(ctx context.Context, id, deliveryID string) (domain.WebhookDelivery, error) {
+	if err := a.policy.Authorize(ctx, authz.WebhooksManage); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return a.next.Redeliver(ctx, id, deliveryID)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/metrics"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of webhook deliveries.
+const (
+	EventHeader     = "X-Geniuscrew-Event"
+	DeliveryHeader  = "X-Geniuscrew-Delivery"
+	SignatureHeader = "X-Geniuscrew-Signature"
+)
+
+// batchSize is the number of deliveries claimed at once.
+const batchSize = 50
+
+// Sign returns the signature header of a delivery of body at t:
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>" keyed with
+// secret>". Receivers recompute it to authenticate the delivery and reject
+// old timestamps to prevent replays.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DispatcherConfig tunes how deliveries are sent.
+type DispatcherConfig struct {
+	// Client sends the deliveries; nil uses http.DefaultClient.
+	Client *http.Client
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a delivery fails.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, doubled after each
+	// further attempt up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// PollInterval is how often the dispatcher looks for due deliveries
+	// queued by other instances or due for a retry.
+	PollInterval time.Duration
+}
+
+// Dispatcher sends pending webhook deliveries, retrying failed ones with
+// exponential backoff. Several instances may share a database; each
+// delivery is claimed by one of them at a time.
+type Dispatcher struct {
+	webhookRepository domain.WebhookRepository
+	cfg               DispatcherConfig
+	wake              chan struct{}
+	now               func() time.Time
+}
+
+func NewDispatcher(w domain.WebhookRepository, cfg DispatcherConfig) *Dispatcher {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &Dispatcher{webhookRepository: w, cfg: cfg, wake: make(chan struct{}, 1), now: time.Now}
+}
+
+// Notify makes Run look for due deliveries now rather than at the next poll.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends due deliveries until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		d.DeliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue makes one attempt at every delivery due now. A delivery is
+// claimed for twice the attempt timeout, after which another instance may
+// attempt it if this one did not record an outcome.
+func (d *Dispatcher) DeliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		batch, err := d.webhookRepository.ClaimDue(ctx, d.now(), 2*d.cfg.Timeout, batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "claiming webhook deliveries failed", "layer", "service", "error", err)
+			}
+			return
+		}
+		for i := range batch {
+			d.attempt(ctx, &batch[i])
+		}
+		if len(batch) < batchSize {
+			return
+		}
+	}
+}
+
+// attempt sends delivery once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	hook, err := d.webhookRepository.Get(ctx, strconv.Itoa(delivery.WebhookID))
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = "webhook deleted"
+		d.record(ctx, delivery, "failed")
+		return
+	case err != nil:
+		// the claim lapses and the delivery is attempted again
+		slog.ErrorContext(ctx, "loading webhook failed", "layer", "service", "webhook_id", delivery.WebhookID, "error", err)
+		return
+	}
+
+	status, err := d.send(ctx, hook, delivery)
+	now := d.now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	switch {
+	case err == nil:
+		delivery.Status = domain.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		d.record(ctx, delivery, "succeeded")
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = truncate(err.Error())
+		d.record(ctx, delivery, "failed")
+	default:
+		delivery.LastError = truncate(err.Error())
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		d.record(ctx, delivery, "retried")
+	}
+}
+
+func (d *Dispatcher) record(ctx context.Context, delivery *domain.WebhookDelivery, outcome string) {
+	metrics.WebhookDeliveries.WithLabelValues(delivery.EventType, outcome).Inc()
+	if err := d.webhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "recording webhook delivery failed", "layer", "service", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+	slog.DebugContext(ctx, "webhook delivery attempted", "layer", "service", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "event", delivery.EventType, "outcome", outcome, "status", delivery.ResponseStatus)
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.Backoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
+
+// send posts the payload of delivery to the webhook and returns the response
+// status. Statuses other than 2xx are errors.
+func (d *Dispatcher) send(ctx context.Context, hook domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "geniuscrew-webhooks/1")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, d.now(), delivery.Payload))
+	res, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+func truncate(message string) string {
+	if len(message) > 1024 {
+		return message[:1024]
+	}
+	return message
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newDispatcher(hookRepo domain.WebhookRepository, now time.Time) *Dispatcher {
+	d := NewDispatcher(hookRepo, DispatcherConfig{
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		Backoff:      10 * time.Second,
+		MaxBackoff:   15 * time.Second,
+		PollInterval: time.Minute,
+	})
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func TestDeliverDue(t *testing.T) {
+	as := assert.New(t)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1","type":"book.created"}`)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		as.Equal(payload, body)
+		as.Equal(domain.EventBookCreated, r.Header.Get(EventHeader))
+		as.Equal("5", r.Header.Get(DeliveryHeader))
+		as.Equal(Sign("whsec_test", now, payload), r.Header.Get(SignatureHeader))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	hook := domain.Webhook{ID: 1, URL: server.URL, Secret: "whsec_test"}
+	due := func(attempts int) []domain.WebhookDelivery {
+		return []domain.WebhookDelivery{{ID: 5, WebhookID: 1, EventType: domain.EventBookCreated, Payload: payload, Status: domain.DeliveryPending, Attempts: attempts}}
+	}
+
+	t.Run("happy path: a 2xx response completes the delivery", func(t *testing.T) {
+		hookRepo := &repository.WebhookRepositoryMock{}
+		hookRepo.On("ClaimDue", mock.Anything, now, 2*time.Second, batchSize).Return(due(0), nil).Once()
+		hookRepo.On("Get", mock.Anything, "1").Return(hook, nil).Once()
+		hookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliverySucceeded && d.Attempts == 1 && d.ResponseStatus == http.StatusOK && d.DeliveredAt != nil
+		})).Return(nil).Once()
+		newDispatcher(hookRepo, now).DeliverDue(context.Background())
+		hookRepo.AssertExpectations(t)
+	})
+
+	t.Run("retry: an error response schedules the next attempt with backoff", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		hookRepo := &repository.WebhookRepositoryMock{}
+		hookRepo.On("ClaimDue", mock.Anything, now, 2*time.Second, batchSize).Return(due(1), nil).Once()
+		hookRepo.On("Get", mock.Anything, "1").Return(hook, nil).Once()
+		hookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+			// the second failure waits twice the backoff, capped at the maximum
+			return d.Status == domain.DeliveryPending && d.Attempts == 2 && d.NextAttemptAt.Equal(now.Add(15*time.Second)) && d.LastError != ""
+		})).Return(nil).Once()
+		newDispatcher(hookRepo, now).DeliverDue(context.Background())
+		hookRepo.AssertExpectations(t)
+	})
+
+	t.Run("failure: the last attempt fails the delivery", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		hookRepo := &repository.WebhookRepositoryMock{}
+		hookRepo.On("ClaimDue", mock.Anything, now, 2*time.Second, batchSize).Return(due(2), nil).Once()
+		hookRepo.On("Get", mock.Anything, "1").Return(hook, nil).Once()
+		hookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryFailed && d.Attempts == 3 && d.ResponseStatus == http.StatusInternalServerError
+		})).Return(nil).Once()
+		newDispatcher(hookRepo, now).DeliverDue(context.Background())
+		hookRepo.AssertExpectations(t)
+	})
+
+	t.Run("failure: deliveries to deleted webhooks fail", func(t *testing.T) {
+		hookRepo := &repository.WebhookRepositoryMock{}
+		hookRepo.On("ClaimDue", mock.Anything, now, 2*time.Second, batchSize).Return(due(0), nil).Once()
+		hookRepo.On("Get", mock.Anything, "1").Return(domain.Webhook{}, domain.ErrRecordNotFound).Once()
+		hookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryFailed && d.Attempts == 0
+		})).Return(nil).Once()
+		newDispatcher(hookRepo, now).DeliverDue(context.Background())
+		hookRepo.AssertExpectations(t)
+	})
+}
+
+func TestSign(t *testing.T) {
+	as := assert.New(t)
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	as.Equal("t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", Sign("secret", time.Unix(1700000000, 0), []byte("{}")))
+}
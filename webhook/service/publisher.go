@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"geniuscrew/domain"
+	"time"
+)
+
+type webhookPublisher struct {
+	webhookRepository domain.WebhookRepository
+	notify            func()
+}
+
+// NewWebhookPublisher returns a publisher that queues a delivery of each
+// event to every webhook subscribed to it and calls notify, e.g. with
+// Dispatcher.Notify, so that they are sent. The outbox publishes an event
+// again after a failure; the deliveries already queued for it are kept.
+func NewWebhookPublisher(w domain.WebhookRepository, notify func()) domain.EventPublisher {
+	return &webhookPublisher{webhookRepository: w, notify: notify}
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event domain.Event) error {
+	hooks, err := p.webhookRepository.List(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	now, original := time.Now(), true
+	var deliveries []domain.WebhookDelivery
+	for _, hook := range hooks {
+		if !hook.Subscribes(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: now,
+			Original:      &original,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err = p.webhookRepository.CreateDeliveries(ctx, deliveries)
+	if err != nil {
+		return err
+	}
+	p.notify()
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"geniuscrew/domain"
+	"geniuscrew/internal/auth"
+	"log/slog"
+	"time"
+)
+
+type webhookService struct {
+	webhookRepository domain.WebhookRepository
+	notify            func()
+	now               func() time.Time
+}
+
+// NewWebhookService manages webhooks in w. notify is called when a delivery
+// is queued, e.g. with Dispatcher.Notify.
+func NewWebhookService(w domain.WebhookRepository, notify func()) domain.WebhookService {
+	return &webhookService{webhookRepository: w, notify: notify, now: time.Now}
+}
+
+func (p *webhookService) Create(ctx context.Context, hook *domain.Webhook) (string, error) {
+	for _, event := range hook.Events {
+		if event != "*" && !known(event) {
+			return "", fmt.Errorf("%w: %s", domain.ErrUnknownEvent, event)
+		}
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	hook.Secret = "whsec_" + hex.EncodeToString(secret)
+	err := p.webhookRepository.Create(ctx, hook)
+	if err != nil {
+		return "", err
+	}
+	slog.InfoContext(ctx, "webhook created", "layer", "service", "actor", auth.Subject(ctx), "webhook_id", hook.ID, "events", hook.Events)
+	return hook.Secret, nil
+}
+
+func (p *webhookService) List(ctx context.Context) ([]domain.Webhook, error) {
+	return p.webhookRepository.List(ctx)
+}
+
+func (p *webhookService) Get(ctx context.Context, id string) (domain.Webhook, error) {
+	return p.webhookRepository.Get(ctx, id)
+}
+
+func (p *webhookService) Delete(ctx context.Context, id string) error {
+	err := p.webhookRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "webhook deleted", "layer", "service", "actor", auth.Subject(ctx), "webhook_id", id)
+	return nil
+}
+
+func (p *webhookService) Deliveries(ctx context.Context, id string, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := p.webhookRepository.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return p.webhookRepository.ListDeliveries(ctx, id, limit)
+}
+
+// Redeliver queues a new delivery with the payload of the original one, so
+// that the delivery log keeps both.
+func (p *webhookService) Redeliver(ctx context.Context, id, deliveryID string) (domain.WebhookDelivery, error) {
+	original, err := p.webhookRepository.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	deliveries := []domain.WebhookDelivery{{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: p.now(),
+		RedeliveryOf:  &original.ID,
+	}}
+	err = p.webhookRepository.CreateDeliveries(ctx, deliveries)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	p.notify()
+	slog.InfoContext(ctx, "webhook redelivery queued", "layer", "service", "actor", auth.Subject(ctx), "webhook_id", id, "delivery_id", original.ID)
+	return deliveries[0], nil
+}
+
+func known(event string) bool {
+	for _, e := range domain.EventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
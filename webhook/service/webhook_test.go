@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"geniuscrew/domain"
+	"geniuscrew/domain/mocks/repository"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreate(t *testing.T) {
+	as := assert.New(t)
+	hookRepo := &repository.WebhookRepositoryMock{}
+	t.Run("happy path: Successfully creates a webhook with a secret", func(t *testing.T) {
+		hookRepo.On("Create", context.Background(), mock.Anything).Return(nil).Once()
+		service := NewWebhookService(hookRepo, func() {})
+		hook := domain.Webhook{URL: "https://example.com/hooks", Events: []string{domain.EventBookCreated, "*"}}
+		secret, err := service.Create(context.Background(), &hook)
+		as.NoError(err)
+		as.True(strings.HasPrefix(secret, "whsec_"))
+		as.Equal(secret, hook.Secret)
+		hookRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: unknown event", func(t *testing.T) {
+		service := NewWebhookService(hookRepo, func() {})
+		_, err := service.Create(context.Background(), &domain.Webhook{Events: []string{"book.read"}})
+		as.True(errors.Is(err, domain.ErrUnknownEvent))
+		hookRepo.AssertExpectations(t)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	as := assert.New(t)
+	hookRepo := &repository.WebhookRepositoryMock{}
+	t.Run("happy path: queues a copy of the delivery", func(t *testing.T) {
+		original := domain.WebhookDelivery{ID: 9, WebhookID: 1, EventID: "evt_1", EventType: domain.EventBookCreated, Payload: []byte(`{}`), Status: domain.DeliveryFailed, Attempts: 8}
+		hookRepo.On("GetDelivery", context.Background(), "1", "9").Return(original, nil).Once()
+		hookRepo.On("CreateDeliveries", context.Background(), mock.MatchedBy(func(d []domain.WebhookDelivery) bool {
+			return len(d) == 1 && d[0].EventID == "evt_1" && d[0].Status == domain.DeliveryPending && d[0].Attempts == 0 && *d[0].RedeliveryOf == 9 && d[0].Original == nil
+		})).Return(nil).Once()
+		notified := false
+		service := NewWebhookService(hookRepo, func() { notified = true })
+		delivery, err := service.Redeliver(context.Background(), "1", "9")
+		as.NoError(err)
+		as.Equal(domain.DeliveryPending, delivery.Status)
+		as.True(notified)
+		hookRepo.AssertExpectations(t)
+	})
+
+	t.Run("input error: delivery not found", func(t *testing.T) {
+		hookRepo.On("GetDelivery", context.Background(), "1", "10").Return(domain.WebhookDelivery{}, domain.ErrRecordNotFound).Once()
+		service := NewWebhookService(hookRepo, func() {})
+		_, err := service.Redeliver(context.Background(), "1", "10")
+		as.True(errors.Is(err, domain.ErrRecordNotFound))
+		hookRepo.AssertExpectations(t)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	as := assert.New(t)
+	hookRepo := &repository.WebhookRepositoryMock{}
+	hookRepo.On("List", context.Background()).Return([]domain.Webhook{
+		{ID: 1, Events: []string{domain.EventBookCreated}},
+		{ID: 2, Events: []string{domain.EventAuthorDeleted}},
+		{ID: 3, Events: []string{"*"}},
+	}, nil).Once()
+	hookRepo.On("CreateDeliveries", context.Background(), mock.MatchedBy(func(d []domain.WebhookDelivery) bool {
+		return len(d) == 2 && d[0].WebhookID == 1 && d[1].WebhookID == 3 && *d[0].Original && strings.Contains(string(d[0].Payload), `"type":"book.created"`)
+	})).Return(nil).Once()
+	notified := false
+	publisher := NewWebhookPublisher(hookRepo, func() { notified = true })
+	as.NoError(publisher.Publish(context.Background(), domain.NewEvent(domain.EventBookCreated, "book/1", map[string]int{"id": 1})))
+	as.True(notified)
+	hookRepo.AssertExpectations(t)
+}